@@ -1,45 +1,78 @@
 package log
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 )
 
-var logFileHandler *os.File
+const (
+	defaultMaxSizeMB = 50
+	defaultMaxFiles  = 10
+	supervisorLogExt = ".log"
+	flushInterval    = time.Second
+)
+
+var (
+	mu              sync.Mutex
+	logDirectory    string
+	rotation        Rotation
+	handlerOpts     *slog.HandlerOptions
+	combinedFile    *rotatingFile
+	combinedHandler slog.Handler
+	supervisorFiles = make(map[string]*rotatingFile)
+	stopFlush       chan struct{}
+)
+
+// Rotation controls the size based rotation of the log files, zero values fall back to the defaults.
+// RetentionDays set to zero keeps the old logs forever.
+type Rotation struct {
+	MaxSizeMB     int
+	MaxFiles      int
+	RetentionDays int
+}
+
+func NewLogger(debug bool, logDir string, rot Rotation) (*slog.Logger, error) {
+	mu.Lock()
+	defer mu.Unlock()
 
-func NewLogger(debug bool, logDir, supervisor string) (*slog.Logger, error) {
 	if logDir == "" {
 		logDir = "logs"
 	}
-
-	if _, err := os.Stat(logDir); errors.Is(err, os.ErrNotExist) {
-		err := os.MkdirAll(logDir, os.ModePerm)
-		if err != nil {
-			return nil, fmt.Errorf("error creating log directory: %w", err)
-		}
+	if rot.MaxSizeMB <= 0 {
+		rot.MaxSizeMB = defaultMaxSizeMB
 	}
+	if rot.MaxFiles <= 0 {
+		rot.MaxFiles = defaultMaxFiles
+	}
+	logDirectory = logDir
+	rotation = rot
 
-	fileName := "Koolo-log-" + time.Now().Format("2006-01-02-15-04-05") + ".txt"
-	if supervisor != "" {
-		fileName = fmt.Sprintf("Supervisor-log-%s-%s.txt", supervisor, time.Now().Format("2006-01-02-15-04-05"))
+	if err := os.MkdirAll(logDir, os.ModePerm); err != nil {
+		return nil, err
 	}
 
-	lfh, err := os.Create(logDir + "/" + fileName)
+	// Cleanup is best effort, an old file we can't remove should never prevent Koolo from starting
+	removeOldLogs(logDir, rot.RetentionDays)
+
+	startedAt := time.Now().Format("2006-01-02-15-04-05")
+	lfh, err := newRotatingFile(logDir, "Koolo-log-", func() string { return "Koolo-log-" + startedAt }, ".txt", rot.maxSizeBytes(), rot.MaxFiles)
 	if err != nil {
 		return nil, err
 	}
-	logFileHandler = lfh
+	combinedFile = lfh
 
 	level := slog.LevelDebug
 	if !debug {
 		level = slog.LevelInfo
 	}
 
-	opts := &slog.HandlerOptions{
+	handlerOpts = &slog.HandlerOptions{
 		Level: level,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key != slog.TimeKey {
@@ -53,16 +86,157 @@ func NewLogger(debug bool, logDir, supervisor string) (*slog.Logger, error) {
 		},
 	}
 
-	handler := slog.NewTextHandler(io.MultiWriter(logFileHandler, os.Stdout), opts)
+	combinedHandler = &flushingHandler{
+		Handler: slog.NewTextHandler(io.MultiWriter(combinedFile, os.Stdout), handlerOpts),
+		file:    combinedFile,
+	}
+
+	stopFlush = make(chan struct{})
+	go flushPeriodically(stopFlush)
+
+	return slog.New(combinedHandler), nil
+}
+
+// NewSupervisorLogger returns a logger writing to logs/<supervisor>/<date>.log, every line is also forwarded to the
+// combined log tagged with the supervisor name
+func NewSupervisorLogger(supervisor string) (*slog.Logger, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if combinedHandler == nil {
+		return nil, errors.New("logger is not initialized")
+	}
+
+	rf, found := supervisorFiles[supervisor]
+	if !found {
+		var err error
+		rf, err = newRotatingFile(filepath.Join(logDirectory, supervisor), "", func() string { return time.Now().Format(time.DateOnly) }, supervisorLogExt, rotation.maxSizeBytes(), rotation.MaxFiles)
+		if err != nil {
+			return nil, err
+		}
+		supervisorFiles[supervisor] = rf
+	}
 
-	return slog.New(handler), nil
+	return slog.New(&fanoutHandler{handlers: []slog.Handler{
+		&flushingHandler{Handler: slog.NewTextHandler(rf, handlerOpts), file: rf},
+		combinedHandler.WithAttrs([]slog.Attr{slog.String("supervisor", supervisor)}),
+	}}), nil
 }
 
+// FlushLog flushes and closes all the open log files, combined and per supervisor
 func FlushLog() error {
-	if logFileHandler != nil {
-		logFileHandler.Sync()
-		return logFileHandler.Close()
+	mu.Lock()
+	defer mu.Unlock()
+
+	if stopFlush != nil {
+		close(stopFlush)
+		stopFlush = nil
+	}
+
+	var errs []error
+	if combinedFile != nil {
+		errs = append(errs, combinedFile.Close())
+	}
+	for name, rf := range supervisorFiles {
+		errs = append(errs, rf.Close())
+		delete(supervisorFiles, name)
+	}
+
+	return errors.Join(errs...)
+}
+
+func flushPeriodically(stop chan struct{}) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			mu.Lock()
+			if combinedFile != nil {
+				combinedFile.Flush()
+			}
+			for _, rf := range supervisorFiles {
+				rf.Flush()
+			}
+			mu.Unlock()
+		}
+	}
+}
+
+func (r Rotation) maxSizeBytes() int64 {
+	return int64(r.MaxSizeMB) * 1024 * 1024
+}
+
+// fanoutHandler sends every record to all the wrapped handlers
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, hnd := range h.handlers {
+		if hnd.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, hnd := range h.handlers {
+		if hnd.Enabled(ctx, r.Level) {
+			errs = append(errs, hnd.Handle(ctx, r.Clone()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, hnd := range h.handlers {
+		handlers = append(handlers, hnd.WithAttrs(attrs))
+	}
+
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, hnd := range h.handlers {
+		handlers = append(handlers, hnd.WithGroup(name))
+	}
+
+	return &fanoutHandler{handlers: handlers}
+}
+
+// flushingHandler writes warnings and errors to the disk right away, a panic in a supervisor goroutine kills the
+// process before the periodic flush and those are the lines needed to find out what happened
+type flushingHandler struct {
+	slog.Handler
+	file *rotatingFile
+}
+
+func (h *flushingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if err := h.Handler.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	if r.Level >= slog.LevelWarn {
+		return h.file.Flush()
 	}
 
 	return nil
 }
+
+func (h *flushingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &flushingHandler{Handler: h.Handler.WithAttrs(attrs), file: h.file}
+}
+
+func (h *flushingHandler) WithGroup(name string) slog.Handler {
+	return &flushingHandler{Handler: h.Handler.WithGroup(name), file: h.file}
+}
@@ -0,0 +1,265 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatingFile is a buffered writer that switches to a new file once the current one reaches maxSize
+type rotatingFile struct {
+	mu       sync.Mutex
+	dir      string
+	family   string
+	prefix   func() string
+	ext      string
+	maxSize  int64
+	maxFiles int
+
+	currentPrefix string
+	file          *os.File
+	buf           *bufio.Writer
+	size          int64
+}
+
+// newRotatingFile creates the writer, family is the file name prefix shared by all the files rotated by this writer
+func newRotatingFile(dir, family string, prefix func() string, ext string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("error creating log directory: %w", err)
+	}
+
+	rf := &rotatingFile{
+		dir:      dir,
+		family:   family,
+		prefix:   prefix,
+		ext:      ext,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	// slog handlers write a full line per call, so rotating here never splits a line between two files
+	if rf.prefix() != rf.currentPrefix || (rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.buf.Write(p)
+	rf.size += int64(n)
+
+	return n, err
+}
+
+func (rf *rotatingFile) Flush() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+
+	if err := rf.buf.Flush(); err != nil {
+		return err
+	}
+
+	return rf.file.Sync()
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	return rf.closeFile()
+}
+
+func (rf *rotatingFile) closeFile() error {
+	if rf.file == nil {
+		return nil
+	}
+
+	// Buffered lines must reach the disk before closing, otherwise they are lost on rotation
+	if err := rf.buf.Flush(); err != nil {
+		return err
+	}
+	rf.file.Sync()
+	err := rf.file.Close()
+	rf.file = nil
+
+	return err
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.closeFile(); err != nil {
+		return err
+	}
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	rf.removeExceedingFiles()
+
+	return nil
+}
+
+// open appends to the latest file for the current prefix if it still has room, otherwise creates a new one
+func (rf *rotatingFile) open() error {
+	rf.currentPrefix = rf.prefix()
+
+	idx := rf.latestIndex()
+	path := rf.filePath(idx)
+	fi, err := os.Stat(path)
+	if err == nil && rf.maxSize > 0 && fi.Size() >= rf.maxSize {
+		idx++
+		path = rf.filePath(idx)
+		fi = nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	rf.size = 0
+	if fi != nil {
+		rf.size = fi.Size()
+	}
+	rf.file = f
+	rf.buf = bufio.NewWriter(f)
+
+	return nil
+}
+
+func (rf *rotatingFile) filePath(idx int) string {
+	if idx == 0 {
+		return filepath.Join(rf.dir, rf.currentPrefix+rf.ext)
+	}
+
+	return filepath.Join(rf.dir, fmt.Sprintf("%s.%d%s", rf.currentPrefix, idx, rf.ext))
+}
+
+// latestIndex returns the highest rotation index used by the files of the current prefix
+func (rf *rotatingFile) latestIndex() int {
+	entries, err := os.ReadDir(rf.dir)
+	if err != nil {
+		return 0
+	}
+
+	latest := 0
+	for _, e := range entries {
+		name, found := strings.CutPrefix(e.Name(), rf.currentPrefix+".")
+		if !found {
+			continue
+		}
+		if idx, err := strconv.Atoi(strings.TrimSuffix(name, rf.ext)); err == nil && idx > latest {
+			latest = idx
+		}
+	}
+
+	return latest
+}
+
+// removeExceedingFiles keeps only the newest maxFiles log files in the directory
+func (rf *rotatingFile) removeExceedingFiles() {
+	if rf.maxFiles <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(rf.dir)
+	if err != nil {
+		return
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+
+	current := rf.file.Name()
+	files := make([]logFile, 0)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), rf.family) || !strings.HasSuffix(e.Name(), rf.ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{path: filepath.Join(rf.dir, e.Name()), modTime: info.ModTime()})
+	}
+
+	if len(files) <= rf.maxFiles {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	for _, f := range files[rf.maxFiles:] {
+		if f.path != current {
+			os.Remove(f.path)
+		}
+	}
+}
+
+// removeOldLogs deletes the log files created by koolo older than the retention period, including supervisor folders
+func removeOldLogs(dir string, retentionDays int) error {
+	if retentionDays <= 0 {
+		return nil
+	}
+
+	limit := time.Now().AddDate(0, 0, -retentionDays)
+	removeIfOld := func(path string) {
+		if info, err := os.Stat(path); err == nil && info.ModTime().Before(limit) {
+			os.Remove(path)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			if strings.HasPrefix(e.Name(), "Koolo-log-") || strings.HasPrefix(e.Name(), "Supervisor-log-") {
+				removeIfOld(filepath.Join(dir, e.Name()))
+			}
+			continue
+		}
+
+		supervisorEntries, err := os.ReadDir(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		for _, se := range supervisorEntries {
+			if !se.IsDir() && filepath.Ext(se.Name()) == supervisorLogExt {
+				removeIfOld(filepath.Join(dir, e.Name(), se.Name()))
+			}
+		}
+	}
+
+	return nil
+}
@@ -9,24 +9,32 @@ import (
 	"runtime/debug"
 	"bufio"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"time"
 
 	sloggger "github.com/hectorgimenez/koolo/cmd/koolo/log"
 	"github.com/hectorgimenez/koolo/internal/bot"
 	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/event"
+	"github.com/hectorgimenez/koolo/internal/metrics"
 	"github.com/hectorgimenez/koolo/internal/remote/discord"
 	"github.com/hectorgimenez/koolo/internal/remote/telegram"
 	"github.com/hectorgimenez/koolo/internal/server"
 	"github.com/hectorgimenez/koolo/internal/utils"
 	"github.com/hectorgimenez/koolo/internal/utils/winproc"
 	"github.com/inkeliz/gowebview"
+	"github.com/lxn/win"
 	"golang.org/x/sync/errgroup"
 )
 
+// Max time given to the supervisors to finish the current run on shutdown, unless configured
+const defaultDrainTimeout = 90 * time.Second
+
 // Function to send messages to the Telegram chat
 func sendMessage(text string) {
 	// URL encode the text to make sure it is safe for the URL
@@ -99,7 +107,11 @@ func main() {
 		return
 	}
 
-	logger, err := sloggger.NewLogger(config.Koolo.Debug.Log, config.Koolo.LogSaveDirectory, "")
+	logger, err := sloggger.NewLogger(config.Koolo.Debug.Log, config.Koolo.LogSaveDirectory, sloggger.Rotation{
+		MaxSizeMB:     config.Koolo.LogRotation.MaxSizeMB,
+		MaxFiles:      config.Koolo.LogRotation.MaxFiles,
+		RetentionDays: config.Koolo.LogRotation.RetentionDays,
+	})
 	if err != nil {
 		log.Fatalf("Error starting logger: %s", err.Error())
 	}
@@ -117,18 +129,36 @@ func main() {
 	// Run the config file scanning and send messages to Telegram
 	findConfigFiles()
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Ctrl+C and termination requests shut down gracefully, draining the supervisors before exiting
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	ctx, cancel := context.WithCancel(signalCtx)
 	defer cancel()
 
 	g, ctx := errgroup.WithContext(ctx)
 
-	winproc.SetProcessDpiAware.Call() // Set DPI awareness to be able to read the correct scale and show the window correctly
+	// Set DPI awareness to be able to read the correct scale and show the window correctly, per monitor awareness is
+	// required to get the scale of every monitor, older Windows versions only support the system one
+	if winproc.SetProcessDpiAwareness.Find() != nil {
+		winproc.SetProcessDpiAware.Call()
+	} else if ret, _, _ := winproc.SetProcessDpiAwareness.Call(winproc.PROCESS_PER_MONITOR_DPI_AWARE); ret != 0 {
+		winproc.SetProcessDpiAware.Call()
+	}
 
 	eventListener := event.NewListener(logger)
 	manager := bot.NewSupervisorManager(logger, eventListener)
 	scheduler := bot.NewScheduler(manager, logger)
 	go scheduler.Start()
-	srv, err := server.New(logger, manager)
+
+	// Metrics collector is only registered when enabled, so it has no cost otherwise
+	var metricsCollector *metrics.Collector
+	if config.Koolo.Metrics.Enabled {
+		metricsCollector = metrics.NewCollector()
+		eventListener.Register(metricsCollector.Handle)
+	}
+
+	srv, err := server.New(logger, manager, metricsCollector)
 	if err != nil {
 		log.Fatalf("Error starting local server: %s", err.Error())
 	}
@@ -148,12 +178,21 @@ func main() {
 			return fmt.Errorf("error creating webview: %w", err)
 		}
 
+		// Now the window exists, size it using the scale of the monitor it was opened on
+		displayScale = config.GetDisplayScaleForWindow(win.HWND(w.Window()))
 		w.SetSize(&gowebview.Point{
 			X: int64(1280 * displayScale),
 			Y: int64(720 * displayScale),
 		}, gowebview.HintFixed)
 
 		defer w.Destroy()
+
+		// Close the window when shutting down for any other reason, e.g. Ctrl+C, otherwise Run never returns
+		go func() {
+			<-ctx.Done()
+			w.Terminate()
+		}()
+
 		w.Run()
 
 		return nil
@@ -161,7 +200,7 @@ func main() {
 
 	// Discord Bot initialization
 	if config.Koolo.Discord.Enabled {
-		discordBot, err := discord.NewBot(config.Koolo.Discord.Token, config.Koolo.Discord.ChannelID, manager)
+		discordBot, err := discord.NewBot(config.Koolo.Discord.Token, config.Koolo.Discord.ChannelID, manager, logger)
 		if err != nil {
 			logger.Error("Discord could not been initialized", slog.Any("error", err))
 			return
@@ -175,7 +214,7 @@ func main() {
 
 	// Telegram Bot initialization
 	if config.Koolo.Telegram.Enabled {
-		telegramBot, err := telegram.NewBot(config.Koolo.Telegram.Token, config.Koolo.Telegram.ChatID, logger)
+		telegramBot, err := telegram.NewBot(config.Koolo.Telegram.Token, config.Koolo.Telegram.ChatID, manager, logger)
 		if err != nil {
 			logger.Error("Telegram could not been initialized", slog.Any("error", err))
 			return
@@ -192,17 +231,35 @@ func main() {
 		return srv.Listen(8087)
 	})
 
+	pauseWatcher := bot.NewPauseWatcher(manager, logger)
+	g.Go(func() error {
+		return pauseWatcher.Start(ctx)
+	})
+
+	// Event listener keeps running until the supervisors are stopped, otherwise they would block sending events while
+	// draining
+	listenerCtx, stopListener := context.WithCancel(context.Background())
+	defer stopListener()
 	g.Go(func() error {
 		defer cancel()
-		return eventListener.Listen(ctx)
+		return eventListener.Listen(listenerCtx)
 	})
 
 	g.Go(func() error {
 		<-ctx.Done()
 		logger.Info("Koolo shutting down...")
 		cancel()
-		manager.StopAll()
 		scheduler.Stop()
+
+		drainTimeout := time.Duration(config.Koolo.DrainTimeoutSeconds) * time.Second
+		if drainTimeout <= 0 {
+			drainTimeout = defaultDrainTimeout
+		}
+		drainCtx, drainCancel := context.WithTimeout(context.Background(), drainTimeout)
+		manager.StopAll(drainCtx)
+		drainCancel()
+		stopListener()
+
 		err = srv.Stop()
 		if err != nil {
 			logger.Error("error stopping local server", slog.Any("error", err))
@@ -3,6 +3,7 @@ package action
 import (
 	"errors"
 	"fmt"
+	"image"
 	"log/slog"
 	"slices"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/hectorgimenez/d2go/pkg/data/stat"
 	"github.com/hectorgimenez/d2go/pkg/nip"
 	"github.com/hectorgimenez/koolo/internal/action/step"
+	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/event"
 )
 
 func itemFitsInventory(i data.Item) bool {
@@ -82,8 +85,14 @@ func ItemPickup(maxDistance int) error {
 			ctx.Logger.Warn("Failed moving closer to item, trying to pickup anyway")
 		}
 
+		screenshot := dropScreenshot(itemToPickup)
 		err = step.PickupItem(itemToPickup)
 		if err == nil {
+			ctx.CurrentGame.PickedUpItems[itemToPickup.UnitID] = context.PickedUpItem{
+				RunName:    ctx.CurrentGame.CurrentRun,
+				Screenshot: screenshot,
+			}
+			event.Send(event.ItemPickedUp(event.Text(ctx.Name, fmt.Sprintf("Item %s [%d] picked up", itemToPickup.Name, itemToPickup.Quality)), itemToPickup))
 			continue // Item picked up successfully, move to next item
 		}
 
@@ -101,6 +110,35 @@ func ItemPickup(maxDistance int) error {
 		)
 	}
 }
+
+// dropScreenshot captures the item lying on the ground before picking it up, only for items matching the pickit rules
+// and when the screenshot is going to be used by the notifications or saved to disk
+func dropScreenshot(i data.Item) image.Image {
+	ctx := context.Get()
+
+	if !config.Koolo.Debug.Screenshots && !(config.Koolo.Discord.Enabled && config.Koolo.Discord.EnableDropMessages) {
+		return nil
+	}
+
+	if i.IsPotion() || i.Name == "Gold" {
+		return nil
+	}
+
+	if !i.IsRuneword {
+		if _, result := ctx.Data.CharacterCfg.Runtime.Rules.EvaluateAll(i); result == nip.RuleResultNoMatch {
+			return nil
+		}
+	}
+
+	img, err := ctx.GameReader.TryScreenshot()
+	if err != nil {
+		ctx.Logger.Debug("Failed capturing drop screenshot", slog.Any("error", err))
+		return nil
+	}
+
+	return img
+}
+
 func GetItemsToPickup(maxDistance int) []data.Item {
 	ctx := context.Get()
 	ctx.SetLastAction("GetItemsToPickup")
@@ -278,7 +278,18 @@ func stashItemAction(i data.Item, rule string, ruleFile string, skipLogging bool
 
 	// Don't log items that we already have in inventory during first run
 	if !skipLogging {
-		event.Send(event.ItemStashed(event.WithScreenshot(ctx.Name, fmt.Sprintf("Item %s [%d] stashed", i.Name, i.Quality), screenshot), data.Drop{Item: i, Rule: rule, RuleFile: ruleFile}))
+		drop := data.Drop{Item: i, Rule: rule, RuleFile: ruleFile}
+
+		// Prefer the screenshot taken when the item was lying on the ground
+		if pickedUp, found := ctx.CurrentGame.PickedUpItems[i.UnitID]; found {
+			drop.DropLocation = pickedUp.RunName
+			if pickedUp.Screenshot != nil {
+				screenshot = pickedUp.Screenshot
+			}
+			delete(ctx.CurrentGame.PickedUpItems, i.UnitID)
+		}
+
+		event.Send(event.ItemStashed(event.WithScreenshot(ctx.Name, fmt.Sprintf("Item %s [%d] stashed", i.Name, i.Quality), screenshot), drop))
 	}
 
 	return true
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
@@ -16,12 +18,16 @@ import (
 )
 
 type Bot struct {
-	ctx *botCtx.Context
+	ctx          *botCtx.Context
+	draining     atomic.Bool
+	drainStarted chan struct{}
+	drainOnce    sync.Once
 }
 
 func NewBot(ctx *botCtx.Context) *Bot {
 	return &Bot{
-		ctx: ctx,
+		ctx:          ctx,
+		drainStarted: make(chan struct{}),
 	}
 }
 func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
@@ -30,8 +36,11 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 	g, ctx := errgroup.WithContext(ctx)
 
 	gameStartedAt := time.Now()
-	b.ctx.SwitchPriority(botCtx.PriorityNormal) // Restore priority to normal, in case it was stopped in previous game
-	b.ctx.CurrentGame = botCtx.NewGameHelper()  // Reset current game helper structure
+	// Restore priority to normal, in case it was stopped in previous game, a pause is kept until it's resumed
+	if b.ctx.ExecutionPriority != botCtx.PriorityPause {
+		b.ctx.SwitchPriority(botCtx.PriorityNormal)
+	}
+	b.ctx.CurrentGame = botCtx.NewGameHelper() // Reset current game helper structure
 
 	err := b.ctx.GameReader.FetchMapData()
 	if err != nil {
@@ -55,9 +64,7 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 				b.Stop()
 				return nil
 			case <-ticker.C:
-				if b.ctx.ExecutionPriority == botCtx.PriorityPause {
-					continue
-				}
+				// Game data is refreshed even while paused, so the UI keeps showing the live state
 				b.ctx.RefreshGameData()
 			}
 		}
@@ -67,6 +74,7 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 	g.Go(func() error {
 		b.ctx.AttachRoutine(botCtx.PriorityBackground)
 		ticker := time.NewTicker(100 * time.Millisecond)
+		pausedAt := time.Time{}
 		for {
 			select {
 			case <-ctx.Done():
@@ -74,8 +82,18 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 				return nil
 			case <-ticker.C:
 				if b.ctx.ExecutionPriority == botCtx.PriorityPause {
+					if pausedAt.IsZero() {
+						pausedAt = time.Now()
+					}
 					continue
 				}
+
+				// Time spent paused doesn't count for the max game length
+				if !pausedAt.IsZero() {
+					gameStartedAt = gameStartedAt.Add(time.Since(pausedAt))
+					pausedAt = time.Time{}
+				}
+
 				err = b.ctx.HealthManager.HandleHealthAndMana()
 				if err != nil {
 					cancel()
@@ -161,6 +179,11 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 
 		b.ctx.AttachRoutine(botCtx.PriorityNormal)
 		for _, r := range runs {
+			if b.IsDraining() {
+				return nil
+			}
+
+			b.ctx.CurrentGame.CurrentRun = r.Name()
 			event.Send(event.RunStarted(event.Text(b.ctx.Name, fmt.Sprintf("Starting run: %s", r.Name())), r.Name()))
 			err = action.PreRun(firstRun)
 			if err != nil {
@@ -192,7 +215,8 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 				return err
 			}
 
-			err = action.PostRun(r == runs[len(runs)-1])
+			// When draining, the character goes back to town after the current run, no matter if it's the last one
+			err = action.PostRun(r == runs[len(runs)-1] && !b.IsDraining())
 			if err != nil {
 				return err
 			}
@@ -203,6 +227,32 @@ func (b *Bot) Run(ctx context.Context, firstRun bool, runs []run.Run) error {
 	return g.Wait()
 }
 
+// Drain stops executing new runs, the current one is finished and the character returns to town
+func (b *Bot) Drain() {
+	b.draining.Store(true)
+	b.drainOnce.Do(func() {
+		close(b.drainStarted)
+	})
+}
+
+// withDrain returns a context that is also cancelled when the bot starts draining, so long waits don't hold the drain
+func (b *Bot) withDrain(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-b.drainStarted:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+func (b *Bot) IsDraining() bool {
+	return b.draining.Load()
+}
+
 func (b *Bot) Stop() {
 	b.ctx.SwitchPriority(botCtx.PriorityStop)
 	b.ctx.Detach()
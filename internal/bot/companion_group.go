@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+// Areas where the leader is considered to be fighting a boss, followers use it to avoid wandering off
+var companionBossAreas = []area.ID{
+	area.CatacombsLevel4,
+	area.DurielsLair,
+	area.DuranceOfHateLevel3,
+	area.ChaosSanctuary,
+	area.NihlathaksTemple,
+	area.ThroneOfDestruction,
+	area.TheWorldstoneChamber,
+}
+
+type CompanionLeaderState struct {
+	Supervisor    string
+	CharacterName string
+	Area          area.ID
+	Position      data.Position
+	InTown        bool
+	InBossFight   bool
+	GameName      string
+}
+
+type CompanionGroupInfo struct {
+	Leader        string
+	Followers     []string
+	GameName      string
+	LeaderRunning bool
+}
+
+// CompanionGroup coordinates a leader supervisor with its followers, all of them running in the same koolo process
+type CompanionGroup struct {
+	mu              sync.Mutex
+	logger          *slog.Logger
+	leader          string
+	leaderSup       Supervisor
+	followers       map[string]Supervisor
+	pausedFollowers map[string]bool
+	gameName        string
+	gamePassword    string
+	gameStarted     chan struct{}
+}
+
+func newCompanionGroup(leader string, logger *slog.Logger) *CompanionGroup {
+	return &CompanionGroup{
+		logger:          logger,
+		leader:          leader,
+		followers:       make(map[string]Supervisor),
+		pausedFollowers: make(map[string]bool),
+		gameStarted:     make(chan struct{}),
+	}
+}
+
+func (g *CompanionGroup) Leader() string {
+	return g.leader
+}
+
+// Followers returns all the supervisors configured to follow this leader, running or not
+func (g *CompanionGroup) Followers() []string {
+	followers := make([]string, 0)
+	for name, cfg := range config.Characters {
+		if name == "template" || name == g.leader {
+			continue
+		}
+		if cfg.Companion.Enabled && !cfg.Companion.Leader && cfg.Companion.LeaderName == g.leader {
+			followers = append(followers, name)
+		}
+	}
+	sort.Strings(followers)
+
+	return followers
+}
+
+func (g *CompanionGroup) Info() CompanionGroupInfo {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return CompanionGroupInfo{
+		Leader:        g.leader,
+		Followers:     g.Followers(),
+		GameName:      g.gameName,
+		LeaderRunning: g.leaderSup != nil,
+	}
+}
+
+// LeaderState returns a snapshot of the leader position, second value will be false if the leader is not in game
+func (g *CompanionGroup) LeaderState() (CompanionLeaderState, bool) {
+	g.mu.Lock()
+	leaderSup := g.leaderSup
+	gameName := g.gameName
+	g.mu.Unlock()
+
+	if leaderSup == nil || gameName == "" {
+		return CompanionLeaderState{}, false
+	}
+
+	ctx := leaderSup.GetContext()
+	if ctx == nil || ctx.GameReader == nil || !ctx.Manager.InGame() {
+		return CompanionLeaderState{}, false
+	}
+
+	d := leaderSup.GetData()
+	return CompanionLeaderState{
+		Supervisor:    g.leader,
+		CharacterName: d.PlayerUnit.Name,
+		Area:          d.PlayerUnit.Area,
+		Position:      d.PlayerUnit.Position,
+		InTown:        d.PlayerUnit.Area.IsTown(),
+		InBossFight:   slices.Contains(companionBossAreas, d.PlayerUnit.Area),
+		GameName:      gameName,
+	}, true
+}
+
+// WaitForLeaderGame blocks until the leader starts a game different from lastGame, or the context is cancelled
+func (g *CompanionGroup) WaitForLeaderGame(ctx context.Context, lastGame string) (string, string, error) {
+	for {
+		g.mu.Lock()
+		if g.leaderSup != nil && g.gameName != "" && g.gameName != lastGame {
+			name, password := g.gameName, g.gamePassword
+			g.mu.Unlock()
+			return name, password, nil
+		}
+		started := g.gameStarted
+		g.mu.Unlock()
+
+		select {
+		case <-started:
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		}
+	}
+}
+
+func (g *CompanionGroup) setLeader(s Supervisor) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.leaderSup = s
+}
+
+func (g *CompanionGroup) addFollower(name string, s Supervisor) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.followers[name] = s
+}
+
+func (g *CompanionGroup) removeSupervisor(name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if name == g.leader {
+		g.leaderSup = nil
+		g.gameName = ""
+		g.gamePassword = ""
+		return
+	}
+
+	delete(g.followers, name)
+	delete(g.pausedFollowers, name)
+}
+
+// signalGameStarted is called by the leader once the game has been created, it resumes any follower paused due to
+// a leader crash and wakes up all the followers waiting to join
+func (g *CompanionGroup) signalGameStarted(name, password string) {
+	g.mu.Lock()
+	g.gameName = name
+	g.gamePassword = password
+
+	toResume := make(map[string]Supervisor)
+	for followerName := range g.pausedFollowers {
+		if f, found := g.followers[followerName]; found && f.IsPaused() {
+			toResume[followerName] = f
+		}
+		delete(g.pausedFollowers, followerName)
+	}
+
+	close(g.gameStarted)
+	g.gameStarted = make(chan struct{})
+	g.mu.Unlock()
+
+	for followerName, f := range toResume {
+		g.logger.Info("Leader is back, resuming follower", slog.String("leader", g.leader), slog.String("follower", followerName))
+		f.Resume()
+	}
+}
+
+// leaderLost pauses all the running followers, they will be resumed when the leader starts a new game
+func (g *CompanionGroup) leaderLost() {
+	g.mu.Lock()
+	g.leaderSup = nil
+	g.gameName = ""
+	g.gamePassword = ""
+
+	toPause := make(map[string]Supervisor)
+	for followerName, f := range g.followers {
+		if f.IsPaused() {
+			continue
+		}
+		toPause[followerName] = f
+		g.pausedFollowers[followerName] = true
+	}
+	g.mu.Unlock()
+
+	for followerName, f := range toPause {
+		g.logger.Warn("Leader crashed, pausing follower", slog.String("leader", g.leader), slog.String("follower", followerName))
+		f.Pause()
+	}
+}
@@ -1,122 +1,201 @@
 package bot
 
-// TODO companion sueprvisor
-//
-//import (
-//	"context"
-//	"errors"
-//	"fmt"
-//	"log/slog"
-//	"time"
-//
-//	"github.com/hectorgimenez/koolo/internal/action"
-//
-//	"github.com/hectorgimenez/koolo/internal/container"
-//
-//	"github.com/hectorgimenez/koolo/internal/config"
-//	"github.com/hectorgimenez/koolo/internal/event"
-//	"github.com/hectorgimenez/koolo/internal/helper"
-//	"github.com/hectorgimenez/koolo/internal/run"
-//)
-//
-//type CompanionSupervisor struct {
-//	*baseSupervisor
-//}
-//
-//func NewCompanionSupervisor(name string, bot *Bot, runFactory *run.Factory, statsHandler *StatsHandler, c container.Container, pid uint32, hwnd uintptr) (*CompanionSupervisor, error) {
-//	bs, err := newBaseSupervisor(bot, runFactory, name, statsHandler, c)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	return &CompanionSupervisor{
-//		baseSupervisor: bs,
-//	}, nil
-//}
-//
-//// Start will return error if it can not be started, otherwise will always return nil
-//func (s *CompanionSupervisor) Start() error {
-//	ctx, cancel := context.WithCancel(context.Background())
-//	s.cancelFn = cancel
-//
-//	err := s.ensureProcessIsRunningAndPrepare(ctx)
-//	if err != nil {
-//		return fmt.Errorf("error preparing game: %w", err)
-//	}
-//
-//	gameCounter := 0
-//	firstRun := true
-//	err = s.waitUntilCharacterSelectionScreen()
-//	if err != nil {
-//		return fmt.Errorf("error waiting for character selection screen: %w", err)
-//	}
-//
-//	for {
-//		select {
-//		case <-ctx.Done():
-//			return nil
-//		default:
-//			if s.c.CharacterCfg.Companion.Leader {
-//				time.Sleep(time.Second * 5)
-//				gameName, err := s.c.Manager.CreateOnlineGame(gameCounter)
-//				gameCounter++ // Sometimes game is created but error during join, so game name will be in use
-//				if err != nil {
-//					s.c.Logger.Error(fmt.Sprintf("Error creating new game: %s", err.Error()))
-//					continue
-//				}
-//
-//				if config.Koolo.Discord.EnableGameCreatedMessages {
-//					event.Send(event.GameCreated(event.Text(s.name, "New game created: %s"), gameName, config.Characters[s.name].Companion.GamePassword))
-//				} else {
-//					event.Send(event.GameCreated(event.Text(s.name, ""), gameName, config.Characters[s.name].Companion.GamePassword))
-//				}
-//				err = s.startBot(ctx, s.runFactory.BuildRuns(), firstRun)
-//				if err != nil {
-//					return err
-//				}
-//				action.ResetBuffTime(s.Name())
-//				firstRun = false
-//			} else {
-//				s.c.Logger.Debug("Waiting for new game to be created...")
-//				evt := s.c.EventListener.WaitForEvent(ctx)
-//				if gcEvent, ok := evt.(event.GameCreatedEvent); ok && gcEvent.Name != "" {
-//					err = s.c.Manager.JoinOnlineGame(gcEvent.Name, gcEvent.Password)
-//					if err != nil {
-//						s.c.Logger.Error(err.Error())
-//						continue
-//					}
-//
-//					runs := s.runFactory.BuildRuns()
-//					err = s.startBot(ctx, runs, firstRun)
-//					firstRun = false
-//					if err != nil {
-//						return err
-//					}
-//					action.ResetBuffTime(s.Name())
-//				}
-//			}
-//		}
-//	}
-//}
-//
-//func (s *CompanionSupervisor) startBot(ctx context.Context, runs []run.Run, firstRun bool) error {
-//	gameStart := time.Now()
-//	s.logGameStart(runs)
-//	err := s.bot.Run(ctx, firstRun, runs)
-//	if err != nil {
-//		if errors.Is(context.Canceled, ctx.Err()) {
-//			return nil
-//		}
-//		errorMsg := fmt.Sprintf("Game finished with errors, reason: %s. Game total time: %0.2fs", err.Error(), time.Since(gameStart).Seconds())
-//		event.Send(event.GameFinished(event.WithScreenshot(s.name, errorMsg, s.c.Reader.Screenshot()), event.FinishedError))
-//		s.c.Logger.Warn(errorMsg, slog.String("supervisor", s.name))
-//	}
-//	if exitErr := s.c.Manager.ExitGame(); exitErr != nil {
-//		return fmt.Errorf("error exiting game: %s", exitErr)
-//	}
-//	firstRun = false
-//
-//	helper.Sleep(5000)
-//
-//	return nil
-//}
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	ct "github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/event"
+	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/health"
+	"github.com/hectorgimenez/koolo/internal/run"
+	"github.com/hectorgimenez/koolo/internal/utils"
+)
+
+type CompanionSupervisor struct {
+	*baseSupervisor
+	group *CompanionGroup
+}
+
+func (s *CompanionSupervisor) GetData() *game.Data {
+	return s.bot.ctx.Data
+}
+
+func (s *CompanionSupervisor) GetContext() *ct.Context {
+	return s.bot.ctx
+}
+
+func NewCompanionSupervisor(name string, bot *Bot, statsHandler *StatsHandler, group *CompanionGroup) (*CompanionSupervisor, error) {
+	bs, err := newBaseSupervisor(bot, name, statsHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompanionSupervisor{
+		baseSupervisor: bs,
+		group:          group,
+	}, nil
+}
+
+func (s *CompanionSupervisor) isLeader() bool {
+	return s.group.Leader() == s.name
+}
+
+// Start will return error if it can not be started, otherwise will always return nil
+func (s *CompanionSupervisor) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancelFn = cancel
+	defer s.setDrained()
+
+	err := s.ensureProcessIsRunningAndPrepare()
+	if err != nil {
+		return fmt.Errorf("error preparing game: %w", err)
+	}
+
+	err = s.waitUntilCharacterSelectionScreen()
+	if errors.Is(err, errDrained) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error waiting for character selection screen: %w", err)
+	}
+
+	gameCounter := 0
+	lastGame := ""
+	firstRun := true
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			if s.bot.IsDraining() {
+				s.bot.ctx.Logger.Info("Supervisor drained, not starting more games")
+				return nil
+			}
+
+			// Paused supervisors don't create or join new games until they are resumed
+			if s.IsPaused() {
+				utils.Sleep(100)
+				continue
+			}
+
+			if err = s.enterLobby(); err != nil {
+				s.bot.ctx.Logger.Error(err.Error())
+				utils.Sleep(1000)
+				continue
+			}
+
+			if s.isLeader() {
+				// Give some time to the followers to leave the previous game
+				time.Sleep(time.Second * 5)
+				gameName, err := s.bot.ctx.Manager.CreateOnlineGame(gameCounter)
+				gameCounter++ // Sometimes game is created but error during join, so game name will be in use
+				if err != nil {
+					s.bot.ctx.Logger.Error(fmt.Sprintf("Error creating new game: %s", err.Error()))
+					continue
+				}
+
+				gamePassword := config.Characters[s.name].Companion.GamePassword
+				s.group.signalGameStarted(gameName, gamePassword)
+				event.Send(event.GameCreated(event.Text(s.name, fmt.Sprintf("New game created: %s", gameName)), gameName, gamePassword))
+			} else {
+				s.bot.ctx.Logger.Info("Waiting for the leader to start a new game...", slog.String("leader", s.group.Leader()))
+				// Leader may never start another game once it's drained, so the wait is also stopped when draining
+				waitCtx, cancelWait := s.bot.withDrain(ctx)
+				gameName, gamePassword, err := s.group.WaitForLeaderGame(waitCtx, lastGame)
+				cancelWait()
+				if err != nil {
+					return nil
+				}
+				lastGame = gameName
+
+				if err = s.bot.ctx.Manager.JoinOnlineGame(gameName, gamePassword); err != nil {
+					s.bot.ctx.Logger.Error(err.Error())
+					continue
+				}
+				event.Send(event.GameCreated(event.Text(s.name, fmt.Sprintf("Joined leader game: %s", gameName)), gameName, gamePassword))
+			}
+
+			err = s.startBot(ctx, s.buildRuns(lastGame), firstRun)
+			firstRun = false
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *CompanionSupervisor) enterLobby() error {
+	for retryCount := 0; !s.bot.ctx.GameReader.IsInLobby(); retryCount++ {
+		if retryCount >= 5 {
+			return fmt.Errorf("failed to enter bnet lobby after 5 retries")
+		}
+
+		s.bot.ctx.HID.Click(game.LeftButton, 744, 650)
+		utils.Sleep(1000)
+	}
+
+	return nil
+}
+
+// buildRuns returns the leader configured runs, followers just follow the leader until it leaves the joined game
+func (s *CompanionSupervisor) buildRuns(gameName string) []run.Run {
+	if s.isLeader() {
+		return run.BuildRuns(s.bot.ctx.CharacterCfg)
+	}
+
+	return []run.Run{run.NewFollowLeader(gameName, func() (run.LeaderState, bool) {
+		leader, found := s.group.LeaderState()
+		return run.LeaderState{
+			Name:        leader.CharacterName,
+			Area:        leader.Area,
+			Position:    leader.Position,
+			InTown:      leader.InTown,
+			InBossFight: leader.InBossFight,
+			GameName:    leader.GameName,
+		}, found
+	})}
+}
+
+func (s *CompanionSupervisor) startBot(ctx context.Context, runs []run.Run, firstRun bool) error {
+	gameStart := time.Now()
+	s.bot.ctx.LastBuffAt = time.Time{}
+	s.logGameStart(runs)
+	s.bot.ctx.RefreshGameData()
+
+	err := s.bot.Run(ctx, firstRun, runs)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+
+		var gameFinishReason event.FinishReason
+		switch {
+		case errors.Is(err, health.ErrChicken):
+			gameFinishReason = event.FinishedChicken
+		case errors.Is(err, health.ErrMercChicken):
+			gameFinishReason = event.FinishedMercChicken
+		case errors.Is(err, health.ErrDied):
+			gameFinishReason = event.FinishedDied
+		default:
+			gameFinishReason = event.FinishedError
+		}
+		event.Send(event.GameFinished(event.WithScreenshot(s.name, err.Error(), s.eventScreenshot()), gameFinishReason))
+		s.bot.ctx.Logger.Warn(
+			fmt.Sprintf("Game finished with errors, reason: %s. Game total time: %0.2fs", err.Error(), time.Since(gameStart).Seconds()),
+			slog.String("supervisor", s.name),
+		)
+	} else {
+		event.Send(event.GameFinished(event.Text(s.name, "Game finished successfully"), event.FinishedOK))
+	}
+
+	if exitErr := s.bot.ctx.Manager.ExitGame(); exitErr != nil {
+		return fmt.Errorf("error exiting game: %w", exitErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+// Drain stops the supervisor once its current run is finished and the character is back in town, if it takes longer
+// than the context allows, the supervisor is stopped right away
+func (mng *SupervisorManager) Drain(ctx context.Context, supervisor string) {
+	if _, found := mng.RunningSupervisors()[supervisor]; !found {
+		return
+	}
+
+	mng.drain(ctx, []string{supervisor})
+}
+
+// StopAll drains all the running supervisors, the ones not drained before the context is done are stopped right away
+func (mng *SupervisorManager) StopAll(ctx context.Context) {
+	mng.stoppingAll.Store(true)
+
+	supervisors := mng.RunningSupervisors()
+	names := make([]string, 0, len(supervisors))
+	for name := range supervisors {
+		names = append(names, name)
+	}
+
+	mng.drain(ctx, names)
+}
+
+func (mng *SupervisorManager) drain(ctx context.Context, names []string) {
+	type drainResult struct {
+		supervisor string
+		timedOut   bool
+	}
+
+	supervisors := mng.RunningSupervisors()
+	results := make(chan drainResult, len(names))
+	pending := 0
+	for _, name := range names {
+		// It may have been stopped in the meantime, by a crash restart for example
+		s, found := supervisors[name]
+		if !found {
+			continue
+		}
+		pending++
+
+		// A client crash while draining should not start the supervisor again
+		mng.stopCrashDetector(name)
+
+		mng.logger.Info("Draining supervisor, waiting for the current run to finish", slog.String("supervisor", name))
+		event.Send(event.SupervisorDrain(event.Text(name, "Draining, waiting for the current run to finish"), event.DrainStarted))
+
+		drained := s.Drain()
+		go func() {
+			select {
+			case <-drained:
+				results <- drainResult{supervisor: name}
+			case <-ctx.Done():
+				results <- drainResult{supervisor: name, timedOut: true}
+			}
+		}()
+	}
+
+	// Supervisors are stopped from here, one by one as they finish, so the manager maps are never modified concurrently
+	for range pending {
+		r := <-results
+		if r.timedOut {
+			mng.logger.Warn("Supervisor drain timed out, stopping it now", slog.String("supervisor", r.supervisor))
+			event.Send(event.SupervisorDrain(event.Text(r.supervisor, "Drain timed out, stopping now"), event.DrainTimedOut))
+		} else {
+			mng.logger.Info("Supervisor drained", slog.String("supervisor", r.supervisor))
+			event.Send(event.SupervisorDrain(event.Text(r.supervisor, "Drained, stopping"), event.DrainFinished))
+		}
+
+		mng.Stop(r.supervisor)
+	}
+}
@@ -3,7 +3,10 @@ package bot
 import (
 	"fmt"
 	"log/slog"
+	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
@@ -24,8 +27,12 @@ import (
 type SupervisorManager struct {
 	logger         *slog.Logger
 	supervisors    map[string]Supervisor
+	supervisorsMu  sync.RWMutex
 	crashDetectors map[string]*game.CrashDetector
+	stoppingAll    atomic.Bool
 	eventListener  *event.Listener
+	companionMu    sync.Mutex
+	companions     map[string]*CompanionGroup
 }
 
 func NewSupervisorManager(logger *slog.Logger, eventListener *event.Listener) *SupervisorManager {
@@ -34,6 +41,7 @@ func NewSupervisorManager(logger *slog.Logger, eventListener *event.Listener) *S
 		supervisors:    make(map[string]Supervisor),
 		crashDetectors: make(map[string]*game.CrashDetector),
 		eventListener:  eventListener,
+		companions:     make(map[string]*CompanionGroup),
 	}
 }
 
@@ -60,7 +68,7 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 		return fmt.Errorf("error loading config: %w", err)
 	}
 
-	supervisorLogger, err := log.NewLogger(config.Koolo.Debug.Log, config.Koolo.LogSaveDirectory, supervisorName)
+	supervisorLogger, err := log.NewSupervisorLogger(supervisorName)
 	if err != nil {
 		return err
 	}
@@ -83,12 +91,13 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 		return err
 	}
 
-	if oldCrashDetector, exists := mng.crashDetectors[supervisorName]; exists {
-		oldCrashDetector.Stop() // Stop the old crash detector if it exists
-	}
+	// Stop the old crash detector if it exists
+	mng.stopCrashDetector(supervisorName)
 
+	mng.supervisorsMu.Lock()
 	mng.supervisors[supervisorName] = supervisor
 	mng.crashDetectors[supervisorName] = crashDetector
+	mng.supervisorsMu.Unlock()
 
 	if config.Koolo.GameWindowArrangement {
 		go func() {
@@ -110,30 +119,52 @@ func (mng *SupervisorManager) Start(supervisorName string, attachToExisting bool
 	return nil
 }
 
-func (mng *SupervisorManager) StopAll() {
-	for _, s := range mng.supervisors {
-		s.Stop()
-	}
-}
-
 func (mng *SupervisorManager) Stop(supervisor string) {
 
-	s, found := mng.supervisors[supervisor]
+	s, found := mng.RunningSupervisors()[supervisor]
 	if found {
 
 		// Stop the Supervisor
 		s.Stop()
 
 		// Delete him from the list of Supervisors
+		mng.supervisorsMu.Lock()
 		delete(mng.supervisors, supervisor)
+		mng.supervisorsMu.Unlock()
 
-		if cd, ok := mng.crashDetectors[supervisor]; ok {
-			cd.Stop()
-			delete(mng.crashDetectors, supervisor)
+		if group := mng.companionGroup(supervisor); group != nil {
+			group.removeSupervisor(supervisor)
 		}
+
+		mng.stopCrashDetector(supervisor)
 	}
 }
 
+// stopCrashDetector stops the supervisor crash detector, so the client is not restarted anymore
+func (mng *SupervisorManager) stopCrashDetector(supervisor string) {
+	mng.supervisorsMu.Lock()
+	cd, found := mng.crashDetectors[supervisor]
+	delete(mng.crashDetectors, supervisor)
+	mng.supervisorsMu.Unlock()
+
+	if found {
+		cd.Stop()
+	}
+}
+
+// RunningSupervisors returns a copy of the running supervisors, safe to be used from any goroutine
+func (mng *SupervisorManager) RunningSupervisors() map[string]Supervisor {
+	mng.supervisorsMu.RLock()
+	defer mng.supervisorsMu.RUnlock()
+
+	supervisors := make(map[string]Supervisor, len(mng.supervisors))
+	for name, s := range mng.supervisors {
+		supervisors[name] = s
+	}
+
+	return supervisors
+}
+
 func (mng *SupervisorManager) TogglePause(supervisor string) {
 	s, found := mng.supervisors[supervisor]
 	if found {
@@ -236,19 +267,41 @@ func (mng *SupervisorManager) buildSupervisor(supervisorName string, logger *slo
 
 	var supervisor Supervisor
 
-	supervisor, err = NewSinglePlayerSupervisor(supervisorName, bot, statsHandler)
-
-	if err != nil {
-		return nil, nil, err
+	if group := mng.companionGroup(supervisorName); group != nil {
+		companionSupervisor, err := NewCompanionSupervisor(supervisorName, bot, statsHandler, group)
+		if err != nil {
+			return nil, nil, err
+		}
 
+		if group.Leader() == supervisorName {
+			group.setLeader(companionSupervisor)
+		} else {
+			group.addFollower(supervisorName, companionSupervisor)
+		}
+		supervisor = companionSupervisor
+	} else {
+		supervisor, err = NewSinglePlayerSupervisor(supervisorName, bot, statsHandler)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// This function will be used to restart the client - passed to the crashDetector
 	restartFunc := func() {
 		mng.logger.Info("Restarting supervisor after crash", slog.String("supervisor", supervisorName))
+
+		// Don't leave the followers stuck waiting for a leader that is not there anymore
+		if group := mng.companionGroup(supervisorName); group != nil && group.Leader() == supervisorName {
+			group.leaderLost()
+		}
 		mng.Stop(supervisorName)
 		time.Sleep(5 * time.Second) // Wait a bit before restarting
 
+		// Koolo is shutting down, the crashed client is not started again
+		if mng.stoppingAll.Load() {
+			return
+		}
+
 		// Get a list of all available Supervisors
 		supervisorList := mng.AvailableSupervisors()
 
@@ -310,6 +363,52 @@ func (mng *SupervisorManager) buildSupervisor(supervisorName string, logger *slo
 	return supervisor, crashDetector, nil
 }
 
+// companionGroup returns the group the supervisor belongs to, or nil if companion mode is disabled for it
+func (mng *SupervisorManager) companionGroup(supervisorName string) *CompanionGroup {
+	cfg, found := config.Characters[supervisorName]
+	if !found || !cfg.Companion.Enabled {
+		return nil
+	}
+
+	leader := cfg.Companion.LeaderName
+	if cfg.Companion.Leader {
+		leader = supervisorName
+	}
+	if leader == "" {
+		return nil
+	}
+
+	mng.companionMu.Lock()
+	defer mng.companionMu.Unlock()
+
+	group, found := mng.companions[leader]
+	if !found {
+		group = newCompanionGroup(leader, mng.logger)
+		mng.companions[leader] = group
+	}
+
+	return group
+}
+
+// CompanionGroups returns all the configured companion groups, sorted by leader name
+func (mng *SupervisorManager) CompanionGroups() []CompanionGroupInfo {
+	groups := make([]CompanionGroupInfo, 0)
+	for _, name := range mng.AvailableSupervisors() {
+		cfg := config.Characters[name]
+		if !cfg.Companion.Enabled || !cfg.Companion.Leader {
+			continue
+		}
+
+		groups = append(groups, mng.companionGroup(name).Info())
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Leader < groups[j].Leader
+	})
+
+	return groups
+}
+
 func (mng *SupervisorManager) GetSupervisorStats(supervisor string) Stats {
 	if mng.supervisors[supervisor] == nil {
 		return Stats{}
@@ -317,6 +416,41 @@ func (mng *SupervisorManager) GetSupervisorStats(supervisor string) Stats {
 	return mng.supervisors[supervisor].Stats()
 }
 
+// WaitingForTokenAuth prevents launching of other clients while there's a client with TokenAuth still starting
+func (mng *SupervisorManager) WaitingForTokenAuth(supervisor string) bool {
+	// Get the current auth method for the supervisor we wanna start
+	supCfg, found := config.Characters[supervisor]
+	if !found {
+		return false
+	}
+
+	for _, sup := range mng.AvailableSupervisors() {
+
+		// If the current don't check against the one we're trying to launch
+		if sup == supervisor {
+			continue
+		}
+
+		if mng.GetSupervisorStats(sup).SupervisorStatus == Starting {
+
+			// Prevent launching if we're using token auth & another client is starting (no matter what auth method)
+			if supCfg.AuthMethod == "TokenAuth" {
+				return true
+			}
+
+			// Prevent launching if another client that is using token auth is starting
+			sCfg, found := config.Characters[sup]
+			if found {
+				if sCfg.AuthMethod == "TokenAuth" {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 func (mng *SupervisorManager) rearrangeWindows() {
 	width := win.GetSystemMetrics(0)
 	height := win.GetSystemMetrics(1)
@@ -0,0 +1,255 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"github.com/lxn/win"
+)
+
+const (
+	pauseHotkeyID         = 1
+	focusCheckInterval    = 250 // ms
+	resumeAfterFocusDelay = 3 * time.Second
+)
+
+var hotkeyNamedKeys = map[string]uint32{
+	"PAUSE":    win.VK_PAUSE,
+	"INSERT":   win.VK_INSERT,
+	"DELETE":   win.VK_DELETE,
+	"HOME":     win.VK_HOME,
+	"END":      win.VK_END,
+	"PAGEUP":   win.VK_PRIOR,
+	"PAGEDOWN": win.VK_NEXT,
+	"SCROLL":   win.VK_SCROLL,
+}
+
+// PauseWatcher pauses and resumes the supervisors from a global hotkey and, for the characters with PauseOnFocus
+// enabled, while their game window is the foreground window
+type PauseWatcher struct {
+	manager *SupervisorManager
+	logger  *slog.Logger
+	focus   map[string]*focusState
+}
+
+type focusState struct {
+	// pausedByFocus is set when the watcher paused the supervisor, a manual pause is never resumed by the watcher
+	pausedByFocus bool
+	// ignoreFocus is set when the player resumed the supervisor using the hotkey while the window was focused, it's
+	// not paused again until the window loses the focus
+	ignoreFocus bool
+	focusLostAt time.Time
+}
+
+func NewPauseWatcher(manager *SupervisorManager, logger *slog.Logger) *PauseWatcher {
+	return &PauseWatcher{
+		manager: manager,
+		logger:  logger,
+		focus:   make(map[string]*focusState),
+	}
+}
+
+// Start blocks until the context is done. Hotkeys and timers are delivered to the message queue of the thread that
+// registered them, so everything runs on the same locked OS thread. Nothing is started when there is no hotkey and no
+// character has PauseOnFocus enabled.
+func (pw *PauseWatcher) Start(ctx context.Context) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	hotkeyRegistered := false
+	if config.Koolo.PauseHotkey != "" {
+		modifiers, key, err := parseHotkey(config.Koolo.PauseHotkey)
+		if err != nil {
+			pw.logger.Error("Invalid pause hotkey, it will be disabled", slog.Any("error", err))
+		} else if ret, _, err := winproc.RegisterHotKey.Call(0, pauseHotkeyID, uintptr(modifiers|winproc.MOD_NOREPEAT), uintptr(key)); ret == 0 {
+			// Usually the hotkey is already registered by another application
+			pw.logger.Error("Pause hotkey could not be registered", slog.String("hotkey", config.Koolo.PauseHotkey), slog.Any("error", err))
+		} else {
+			defer winproc.UnregisterHotKey.Call(0, pauseHotkeyID)
+			hotkeyRegistered = true
+			pw.logger.Info("Pause hotkey registered", slog.String("hotkey", config.Koolo.PauseHotkey))
+		}
+	}
+
+	watchFocus := false
+	for _, cfg := range config.Characters {
+		watchFocus = watchFocus || cfg.PauseOnFocus
+	}
+
+	if !hotkeyRegistered && !watchFocus {
+		return nil
+	}
+
+	if watchFocus {
+		timerID := win.SetTimer(0, 0, focusCheckInterval, 0)
+		defer win.KillTimer(0, timerID)
+	}
+
+	threadID := win.GetCurrentThreadId()
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			winproc.PostThreadMessage.Call(uintptr(threadID), win.WM_QUIT, 0, 0)
+		case <-stopped:
+		}
+	}()
+
+	var msg win.MSG
+	for win.GetMessage(&msg, 0, 0, 0) > 0 {
+		switch msg.Message {
+		case win.WM_HOTKEY:
+			if msg.WParam == pauseHotkeyID {
+				pw.toggleFocusedSupervisor()
+			}
+		case win.WM_TIMER:
+			pw.checkFocus()
+		}
+	}
+
+	return nil
+}
+
+func (pw *PauseWatcher) toggleFocusedSupervisor() {
+	name, s, found := focusedSupervisor(pw.manager.RunningSupervisors())
+	if !found {
+		pw.logger.Debug("Pause hotkey pressed, but the focused window is not attached to any supervisor")
+		return
+	}
+
+	// Resuming a supervisor paused by the focus means the player wants it running, even with the window focused
+	if st, found := pw.focus[name]; found && st.pausedByFocus {
+		st.pausedByFocus = false
+		st.ignoreFocus = true
+		s.Resume()
+		return
+	}
+
+	s.TogglePause()
+}
+
+func (pw *PauseWatcher) checkFocus() {
+	supervisors := pw.manager.RunningSupervisors()
+	focused, _, _ := focusedSupervisor(supervisors)
+
+	for name := range pw.focus {
+		if _, found := supervisors[name]; !found {
+			delete(pw.focus, name)
+		}
+	}
+
+	for name, s := range supervisors {
+		st, found := pw.focus[name]
+		if !found {
+			st = &focusState{}
+			pw.focus[name] = st
+		}
+
+		cfg, found := config.Characters[name]
+		if !found || !cfg.PauseOnFocus {
+			if st.pausedByFocus {
+				s.Resume()
+			}
+			*st = focusState{}
+			continue
+		}
+
+		if name == focused {
+			st.focusLostAt = time.Time{}
+			if !st.pausedByFocus && !st.ignoreFocus && !s.IsPaused() {
+				pw.logger.Info("Game window focused, pausing", slog.String("supervisor", name))
+				st.pausedByFocus = true
+				s.Pause()
+			}
+			continue
+		}
+
+		st.ignoreFocus = false
+		if !st.pausedByFocus {
+			continue
+		}
+
+		// Small delay before resuming, so the bot doesn't take over while the player is switching between windows
+		if st.focusLostAt.IsZero() {
+			st.focusLostAt = time.Now()
+			continue
+		}
+		if time.Since(st.focusLostAt) >= resumeAfterFocusDelay {
+			pw.logger.Info("Game window lost the focus, resuming", slog.String("supervisor", name))
+			*st = focusState{}
+			s.Resume()
+		}
+	}
+}
+
+func focusedSupervisor(supervisors map[string]Supervisor) (string, Supervisor, bool) {
+	hwnd := win.GetForegroundWindow()
+	if hwnd == 0 {
+		return "", nil, false
+	}
+
+	for name, s := range supervisors {
+		ctx := s.GetContext()
+		if ctx != nil && ctx.GameReader != nil && ctx.GameReader.HWND == hwnd {
+			return name, s, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// parseHotkey converts a hotkey like "Ctrl+Shift+F12" to the modifiers and virtual key code used by RegisterHotKey
+func parseHotkey(hotkey string) (uint32, uint32, error) {
+	var modifiers, key uint32
+	for _, part := range strings.Split(strings.ToUpper(hotkey), "+") {
+		part = strings.TrimSpace(part)
+		if key != 0 {
+			return 0, 0, fmt.Errorf("hotkey %s has more than one key, the key should be the last one", hotkey)
+		}
+
+		switch part {
+		case "CTRL", "CONTROL":
+			modifiers |= winproc.MOD_CONTROL
+		case "ALT":
+			modifiers |= winproc.MOD_ALT
+		case "SHIFT":
+			modifiers |= winproc.MOD_SHIFT
+		default:
+			key = hotkeyVirtualKey(part)
+			if key == 0 {
+				return 0, 0, fmt.Errorf("unknown key %s in hotkey %s", part, hotkey)
+			}
+		}
+	}
+
+	if key == 0 {
+		return 0, 0, fmt.Errorf("hotkey %s has no key, only modifiers", hotkey)
+	}
+
+	return modifiers, key, nil
+}
+
+func hotkeyVirtualKey(key string) uint32 {
+	if vk, found := hotkeyNamedKeys[key]; found {
+		return vk
+	}
+
+	// Letters and numbers virtual key codes are the same as the ASCII ones
+	if len(key) == 1 && (key[0] >= 'A' && key[0] <= 'Z' || key[0] >= '0' && key[0] <= '9') {
+		return uint32(key[0])
+	}
+
+	if n, err := strconv.Atoi(strings.TrimPrefix(key, "F")); err == nil && strings.HasPrefix(key, "F") && n >= 1 && n <= 24 {
+		return uint32(win.VK_F1 + n - 1)
+	}
+
+	return 0
+}
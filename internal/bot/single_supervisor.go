@@ -45,6 +45,7 @@ func NewSinglePlayerSupervisor(name string, bot *Bot, statsHandler *StatsHandler
 func (s *SinglePlayerSupervisor) Start() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	s.cancelFn = cancel
+	defer s.setDrained()
 
 	err := s.ensureProcessIsRunningAndPrepare()
 	if err != nil {
@@ -57,8 +58,22 @@ func (s *SinglePlayerSupervisor) Start() error {
 		case <-ctx.Done():
 			return nil
 		default:
+			if s.bot.IsDraining() {
+				s.bot.ctx.Logger.Info("Supervisor drained, not starting more games")
+				return nil
+			}
+
+			// Paused supervisors don't create or join new games until they are resumed
+			if s.IsPaused() {
+				utils.Sleep(100)
+				continue
+			}
+
 			if firstRun {
 				err = s.waitUntilCharacterSelectionScreen()
+				if errors.Is(err, errDrained) {
+					return nil
+				}
 				if err != nil {
 					return fmt.Errorf("error waiting for character selection screen: %w", err)
 				}
@@ -121,7 +136,7 @@ func (s *SinglePlayerSupervisor) Start() error {
 				default:
 					gameFinishReason = event.FinishedError
 				}
-				event.Send(event.GameFinished(event.WithScreenshot(s.name, err.Error(), s.bot.ctx.GameReader.Screenshot()), gameFinishReason))
+				event.Send(event.GameFinished(event.WithScreenshot(s.name, err.Error(), s.eventScreenshot()), gameFinishReason))
 				s.bot.ctx.Logger.Warn(
 					fmt.Sprintf("Game finished with errors, reason: %s. Game total time: %0.2fs", err.Error(), time.Since(gameStart).Seconds()),
 					slog.String("supervisor", s.name),
@@ -134,7 +149,7 @@ func (s *SinglePlayerSupervisor) Start() error {
 
 			if exitErr := s.bot.ctx.Manager.ExitGame(); exitErr != nil {
 				errMsg := fmt.Sprintf("Error exiting game %s", exitErr.Error())
-				event.Send(event.GameFinished(event.WithScreenshot(s.name, errMsg, s.bot.ctx.GameReader.Screenshot()), event.FinishedError))
+				event.Send(event.GameFinished(event.WithScreenshot(s.name, errMsg, s.eventScreenshot()), event.FinishedError))
 				return errors.New(errMsg)
 			}
 		}
@@ -2,12 +2,16 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"image"
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hectorgimenez/koolo/internal/config"
 	ct "github.com/hectorgimenez/koolo/internal/context"
 	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/game"
@@ -16,12 +20,19 @@ import (
 	"github.com/lxn/win"
 )
 
+// errDrained is returned by the waits interrupted because the supervisor started draining
+var errDrained = errors.New("supervisor is draining")
+
 type Supervisor interface {
 	Start() error
 	Name() string
 	Stop()
+	Drain() <-chan struct{}
 	Stats() Stats
 	TogglePause()
+	Pause()
+	Resume()
+	IsPaused() bool
 	SetWindowPosition(x, y int)
 	GetData() *game.Data
 	GetContext() *ct.Context
@@ -32,6 +43,8 @@ type baseSupervisor struct {
 	name         string
 	statsHandler *StatsHandler
 	cancelFn     context.CancelFunc
+	drained      chan struct{}
+	drainedOnce  sync.Once
 }
 
 func newBaseSupervisor(
@@ -43,6 +56,7 @@ func newBaseSupervisor(
 		bot:          bot,
 		name:         name,
 		statsHandler: statsHandler,
+		drained:      make(chan struct{}),
 	}, nil
 }
 
@@ -55,17 +69,43 @@ func (s *baseSupervisor) Stats() Stats {
 }
 
 func (s *baseSupervisor) TogglePause() {
-	if s.bot.ctx.ExecutionPriority == ct.PriorityPause {
-		s.bot.ctx.MemoryInjector.Load()
-		s.bot.ctx.SwitchPriority(ct.PriorityNormal)
-		s.bot.ctx.Logger.Info("Resuming...", slog.String("configuration", s.name))
-		event.Send(event.GamePaused(event.Text(s.name, "Game resumed"), false))
+	if s.IsPaused() {
+		s.Resume()
 	} else {
-		s.bot.ctx.SwitchPriority(ct.PriorityPause)
-		s.bot.ctx.MemoryInjector.RestoreMemory()
-		s.bot.ctx.Logger.Info("Pausing...", slog.String("configuration", s.name))
-		event.Send(event.GamePaused(event.Text(s.name, "Game paused"), true))
+		s.Pause()
+	}
+}
+
+func (s *baseSupervisor) IsPaused() bool {
+	return s.bot.ctx.ExecutionPriority == ct.PriorityPause
+}
+
+// Pause blocks the bot input right away and stops the bot at the next step, game data is still read so the UI shows
+// the live state
+func (s *baseSupervisor) Pause() {
+	if s.IsPaused() {
+		return
+	}
+
+	s.bot.ctx.SwitchPriority(ct.PriorityPause)
+	// Waits for the input being sent, so the memory is not restored while the bot is still using it
+	s.bot.ctx.HID.Pause()
+	s.bot.ctx.MemoryInjector.RestoreMemory()
+	s.bot.ctx.Logger.Info("Pausing...", slog.String("configuration", s.name))
+	event.Send(event.GamePaused(event.Text(s.name, "Game paused"), true))
+}
+
+// Resume gives the control back to the bot, the interrupted step is evaluated again with the current game data
+func (s *baseSupervisor) Resume() {
+	if !s.IsPaused() {
+		return
 	}
+
+	s.bot.ctx.MemoryInjector.Load()
+	s.bot.ctx.SwitchPriority(ct.PriorityNormal)
+	s.bot.ctx.HID.Resume()
+	s.bot.ctx.Logger.Info("Resuming...", slog.String("configuration", s.name))
+	event.Send(event.GamePaused(event.Text(s.name, "Game resumed"), false))
 }
 
 func (s *baseSupervisor) Stop() {
@@ -75,6 +115,8 @@ func (s *baseSupervisor) Stop() {
 	}
 
 	s.bot.ctx.SwitchPriority(ct.PriorityStop)
+	// Releases the input blocked by a pause, the bot is finishing anyway
+	s.bot.ctx.HID.Resume()
 
 	s.bot.ctx.MemoryInjector.Unload()
 	s.bot.ctx.GameReader.Close()
@@ -86,6 +128,23 @@ func (s *baseSupervisor) Stop() {
 	s.bot.ctx.Logger.Info("Finished stopping", slog.String("configuration", s.name))
 }
 
+// Drain asks the supervisor to stop creating games once the current run is finished and the character is back in
+// town, the returned channel is closed when the supervisor is done. A paused supervisor is considered drained.
+func (s *baseSupervisor) Drain() <-chan struct{} {
+	s.bot.Drain()
+	if s.bot.ctx.ExecutionPriority == ct.PriorityPause {
+		s.setDrained()
+	}
+
+	return s.drained
+}
+
+func (s *baseSupervisor) setDrained() {
+	s.drainedOnce.Do(func() {
+		close(s.drained)
+	})
+}
+
 func (s *baseSupervisor) KillClient() error {
 
 	process, err := os.FindProcess(int(s.bot.ctx.GameReader.Process.GetPID()))
@@ -120,6 +179,10 @@ func (s *baseSupervisor) waitUntilCharacterSelectionScreen() error {
 	s.bot.ctx.Logger.Info("Waiting for character selection screen...")
 
 	for !s.bot.ctx.GameReader.IsInCharacterSelectionScreen() {
+		s.waitWhilePaused()
+		if s.bot.IsDraining() {
+			return errDrained
+		}
 		// Spam left click to skip to the char select screen
 		s.bot.ctx.HID.Click(game.LeftButton, 100, 100)
 		time.Sleep(250 * time.Millisecond)
@@ -131,6 +194,10 @@ func (s *baseSupervisor) waitUntilCharacterSelectionScreen() error {
 		s.bot.ctx.Logger.Info("Selecting character...")
 		previousSelection := ""
 		for {
+			s.waitWhilePaused()
+			if s.bot.IsDraining() {
+				return errDrained
+			}
 			characterName := s.bot.ctx.GameReader.GameReader.GetSelectedCharacterName()
 			if strings.EqualFold(previousSelection, characterName) {
 				return fmt.Errorf("character %s not found", s.bot.ctx.CharacterCfg.CharacterName)
@@ -149,6 +216,29 @@ func (s *baseSupervisor) waitUntilCharacterSelectionScreen() error {
 	return nil
 }
 
+// waitWhilePaused blocks the out of game flows while paused, in game steps are already blocked by their priority
+func (s *baseSupervisor) waitWhilePaused() {
+	for s.IsPaused() {
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// eventScreenshot captures the game window to be attached to an event, if the capture fails the event should still
+// be sent, so the error is only logged
+func (s *baseSupervisor) eventScreenshot() image.Image {
+	if !config.Koolo.Debug.Screenshots {
+		return nil
+	}
+
+	img, err := s.bot.ctx.GameReader.TryScreenshot()
+	if err != nil {
+		s.bot.ctx.Logger.Warn("Could not capture game window screenshot", slog.Any("error", err))
+		return nil
+	}
+
+	return img
+}
+
 func (s *baseSupervisor) SetWindowPosition(x, y int) {
 	uFlags := win.SWP_NOZORDER | win.SWP_NOSIZE | win.SWP_NOACTIVATE
 	win.SetWindowPos(s.bot.ctx.GameReader.HWND, 0, int32(x), int32(y), 0, 0, uint32(uFlags))
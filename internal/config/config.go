@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/hectorgimenez/d2go/pkg/data"
@@ -22,6 +23,15 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	ErrInvalidSupervisorName = errors.New("invalid supervisor name")
+	ErrSupervisorExists      = errors.New("configuration with that name already exists")
+	ErrSupervisorNotFound    = errors.New("configuration not found")
+
+	reservedWindowsNames = []string{"CON", "PRN", "AUX", "NUL", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7",
+		"COM8", "COM9", "LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9"}
+)
+
 var (
 	Koolo      *KooloCfg
 	Characters map[string]*CharacterCfg
@@ -30,9 +40,10 @@ var (
 
 type KooloCfg struct {
 	Debug struct {
-		Log         bool `yaml:"log"`
-		Screenshots bool `yaml:"screenshots"`
-		RenderMap   bool `yaml:"renderMap"`
+		Log            bool `yaml:"log"`
+		Screenshots    bool `yaml:"screenshots"`
+		MaxScreenshots int  `yaml:"maxScreenshots"`
+		RenderMap      bool `yaml:"renderMap"`
 	} `yaml:"debug"`
 	FirstRun              bool   `yaml:"firstRun"`
 	UseCustomSettings     bool   `yaml:"useCustomSettings"`
@@ -46,15 +57,35 @@ type KooloCfg struct {
 		EnableNewRunMessages         bool     `yaml:"enableNewRunMessages"`
 		EnableRunFinishMessages      bool     `yaml:"enableRunFinishMessages"`
 		EnableDiscordChickenMessages bool     `yaml:"enableDiscordChickenMessages"`
+		EnableDeathMessages          bool     `yaml:"enableDeathMessages"`
+		EnableErrorMessages          bool     `yaml:"enableErrorMessages"`
+		EnableDropMessages           bool     `yaml:"enableDropMessages"`
+		DropQualities                []string `yaml:"dropQualities"`
+		EnableRunSummaryMessages     bool     `yaml:"enableRunSummaryMessages"`
+		RunSummaryGames              int      `yaml:"runSummaryGames"`
+		RunSummaryMinutes            int      `yaml:"runSummaryMinutes"`
 		BotAdmins                    []string `yaml:"botAdmins"`
 		ChannelID                    string   `yaml:"channelId"`
 		Token                        string   `yaml:"token"`
 	} `yaml:"discord"`
+	LogRotation struct {
+		MaxSizeMB     int `yaml:"maxSizeMB"`
+		MaxFiles      int `yaml:"maxFiles"`
+		RetentionDays int `yaml:"retentionDays"`
+	} `yaml:"logRotation"`
 	Telegram struct {
 		Enabled bool   `yaml:"enabled"`
 		ChatID  int64  `yaml:"chatId"`
 		Token   string `yaml:"token"`
 	}
+	Metrics struct {
+		Enabled bool `yaml:"enabled"`
+	} `yaml:"metrics"`
+	Server struct {
+		APIToken string `yaml:"apiToken"`
+	} `yaml:"server"`
+	DrainTimeoutSeconds int    `yaml:"drainTimeoutSeconds"`
+	PauseHotkey         string `yaml:"pauseHotkey"`
 }
 
 type Day struct {
@@ -84,6 +115,7 @@ type CharacterCfg struct {
 	KillD2OnStop    bool   `yaml:"killD2OnStop"`
 	ClassicMode     bool   `yaml:"classicMode"`
 	CloseMiniPanel  bool   `yaml:"closeMiniPanel"`
+	PauseOnFocus    bool   `yaml:"pauseOnFocus"`
 
 	Scheduler Scheduler `yaml:"scheduler"`
 	Health    struct {
@@ -212,6 +244,7 @@ type CharacterCfg struct {
 		} `yaml:"quests"`
 	} `yaml:"game"`
 	Companion struct {
+		Enabled          bool   `yaml:"enabled"`
 		Leader           bool   `yaml:"leader"`
 		LeaderName       string `yaml:"leaderName"`
 		GameNameTemplate string `yaml:"gameNameTemplate"`
@@ -261,6 +294,17 @@ func (bm BeltColumns) Total(potionType data.PotionType) int {
 }
 
 // Load reads the config.ini file and returns a Config struct filled with data from the ini file
+// newKooloCfg returns the defaults for the settings missing in koolo.yaml files created by older versions, so
+// upgrading doesn't silently disable what was enabled before they existed
+func newKooloCfg() *KooloCfg {
+	cfg := &KooloCfg{}
+	cfg.Discord.EnableDeathMessages = true
+	cfg.Discord.EnableErrorMessages = true
+	cfg.Discord.EnableDropMessages = true
+
+	return cfg
+}
+
 func Load() error {
 	Characters = make(map[string]*CharacterCfg)
 
@@ -282,10 +326,12 @@ func Load() error {
 	}
 	defer r.Close()
 
+	kooloCfg := newKooloCfg()
 	d := yaml.NewDecoder(r)
-	if err = d.Decode(&Koolo); err != nil {
+	if err = d.Decode(kooloCfg); err != nil {
 		return fmt.Errorf("error reading config %s: %w", kooloPath, err)
 	}
+	Koolo = kooloCfg
 
 	configDir := getAbsPath("config")
 	entries, err := os.ReadDir(configDir)
@@ -338,22 +384,95 @@ func Load() error {
 }
 
 func CreateFromTemplate(name string) error {
-	if name == "" {
-		return errors.New("name cannot be empty")
+	return createSupervisorConfig(name, "template")
+}
+
+// CloneSupervisorConfig creates a new supervisor configuration copying the settings and pickit rules from an existing one
+func CloneSupervisorConfig(name, from string) error {
+	if _, found := Characters[from]; !found || from == "template" {
+		return fmt.Errorf("%w: %s", ErrSupervisorNotFound, from)
 	}
 
-	if _, err := os.Stat("config/" + name); !os.IsNotExist(err) {
-		return errors.New("configuration with that name already exists")
+	return createSupervisorConfig(name, from)
+}
+
+func createSupervisorConfig(name, from string) error {
+	if err := ValidateSupervisorName(name); err != nil {
+		return err
+	}
+
+	source, found := Characters[from]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrSupervisorNotFound, from)
 	}
 
-	err := cp.Copy("config/template", "config/"+name)
+	err := cp.Copy("config/"+from, "config/"+name)
 	if err != nil {
-		return fmt.Errorf("error copying template: %w", err)
+		return fmt.Errorf("error copying %s: %w", from, err)
+	}
+
+	// Config is written again from the parsed one, so it's always valid and up to date with the current fields
+	cfg := *source
+	cfg.Validate()
+	if err = SaveSupervisorConfig(name, &cfg); err != nil {
+		os.RemoveAll("config/" + name)
+		Load()
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSupervisorConfig removes the supervisor configuration folder, it's up to the caller to ensure it's not running
+func DeleteSupervisorConfig(name string) error {
+	if _, found := Characters[name]; !found || name == "template" {
+		return fmt.Errorf("%w: %s", ErrSupervisorNotFound, name)
+	}
+
+	if err := os.RemoveAll("config/" + name); err != nil {
+		return fmt.Errorf("error removing configuration: %w", err)
 	}
 
 	return Load()
 }
 
+// ValidateSupervisorName checks the name can be used as folder name on Windows and it's not in use, ignoring case
+// because Windows paths are case-insensitive
+func ValidateSupervisorName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidSupervisorName)
+	}
+
+	if strings.EqualFold(name, "template") {
+		return fmt.Errorf("%w: template is a reserved name", ErrInvalidSupervisorName)
+	}
+
+	if strings.ContainsAny(name, `<>:"/\|?*`) || strings.IndexFunc(name, func(r rune) bool { return r < 32 }) >= 0 {
+		return fmt.Errorf(`%w: name cannot contain any of the following characters: < > : " / \ | ? *`, ErrInvalidSupervisorName)
+	}
+
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") || strings.HasPrefix(name, " ") {
+		return fmt.Errorf("%w: name cannot start with a space or end with a space or a dot", ErrInvalidSupervisorName)
+	}
+
+	baseName, _, _ := strings.Cut(strings.ToUpper(name), ".")
+	if slices.Contains(reservedWindowsNames, baseName) {
+		return fmt.Errorf("%w: %s is a reserved name on Windows", ErrInvalidSupervisorName, name)
+	}
+
+	for existing := range Characters {
+		if strings.EqualFold(existing, name) {
+			return fmt.Errorf("%w: %s", ErrSupervisorExists, existing)
+		}
+	}
+
+	if _, err := os.Stat("config/" + name); !os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrSupervisorExists, name)
+	}
+
+	return nil
+}
+
 func ValidateAndSaveConfig(config KooloCfg) error {
 	// Trim executable from the path, just in case
 	config.D2LoDPath = strings.ReplaceAll(strings.ToLower(config.D2LoDPath), "game.exe", "")
@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"sync"
+	"unsafe"
 
+	"github.com/hectorgimenez/koolo/internal/utils/winproc"
 	"github.com/lxn/win"
 	cp "github.com/otiai10/copy"
 )
@@ -11,6 +14,16 @@ import (
 var userProfile = os.Getenv("USERPROFILE")
 var settingsPath = userProfile + "\\Saved Games\\Diablo II Resurrected"
 
+var (
+	displayScaleMu    sync.Mutex
+	windowScaleCached = make(map[win.HWND]windowScale)
+)
+
+type windowScale struct {
+	monitor win.HMONITOR
+	scale   float64
+}
+
 func ReplaceGameSettings(modName string) error {
 	modDirPath := settingsPath + "\\mods\\" + modName
 	modSettingsPath := modDirPath + "\\Settings.json"
@@ -62,3 +75,36 @@ func GetCurrentDisplayScale() float64 {
 
 	return float64(dpiX) / 96.0
 }
+
+// GetDisplayScaleForWindow returns the scale of the monitor where the window is placed, it requires the process to be
+// per monitor DPI aware. The scale is cached per window until it's moved to another monitor, falling back to
+// GetCurrentDisplayScale if the window is not available yet.
+func GetDisplayScaleForWindow(hwnd win.HWND) float64 {
+	displayScaleMu.Lock()
+	defer displayScaleMu.Unlock()
+
+	if isWindow, _, _ := winproc.IsWindow.Call(uintptr(hwnd)); hwnd == 0 || isWindow == 0 {
+		delete(windowScaleCached, hwnd)
+		return GetCurrentDisplayScale()
+	}
+
+	monitor := win.MonitorFromWindow(hwnd, win.MONITOR_DEFAULTTONEAREST)
+	if cached, found := windowScaleCached[hwnd]; found && cached.monitor == monitor {
+		return cached.scale
+	}
+
+	if monitor == 0 || winproc.GetDpiForMonitor.Find() != nil {
+		return GetCurrentDisplayScale()
+	}
+
+	var dpiX, dpiY uint32
+	ret, _, _ := winproc.GetDpiForMonitor.Call(uintptr(monitor), winproc.MDT_EFFECTIVE_DPI, uintptr(unsafe.Pointer(&dpiX)), uintptr(unsafe.Pointer(&dpiY)))
+	if ret != 0 || dpiX == 0 {
+		return GetCurrentDisplayScale()
+	}
+
+	scale := float64(dpiX) / 96.0
+	windowScaleCached[hwnd] = windowScale{monitor: monitor, scale: scale}
+
+	return scale
+}
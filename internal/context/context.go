@@ -1,6 +1,7 @@
 package context
 
 import (
+	"image"
 	"log/slog"
 	"runtime"
 	"strconv"
@@ -66,7 +67,15 @@ type CurrentGameHelper struct {
 		Enabled      bool
 		ExpectedArea area.ID
 	}
-	PickupItems bool
+	PickupItems   bool
+	CurrentRun    string
+	PickedUpItems map[data.UnitID]PickedUpItem
+}
+
+// PickedUpItem keeps where an item was picked up, so it can be reported once it's stashed
+type PickedUpItem struct {
+	RunName    string
+	Screenshot image.Image
 }
 
 func NewContext(name string) *Status {
@@ -81,7 +90,7 @@ func NewContext(name string) *Status {
 			PriorityPause:      {},
 			PriorityStop:       {},
 		},
-		CurrentGame: &CurrentGameHelper{},
+		CurrentGame: &CurrentGameHelper{PickedUpItems: make(map[data.UnitID]PickedUpItem)},
 	}
 	botContexts[getGoroutineID()] = &Status{Priority: PriorityNormal, Context: ctx}
 
@@ -90,7 +99,8 @@ func NewContext(name string) *Status {
 
 func NewGameHelper() *CurrentGameHelper {
 	return &CurrentGameHelper{
-		PickupItems: true,
+		PickupItems:   true,
+		PickedUpItems: make(map[data.UnitID]PickedUpItem),
 	}
 }
 
@@ -152,13 +162,21 @@ func (s *Status) PauseIfNotPriority() {
 		time.Sleep(time.Millisecond * 5)
 	}
 
+	waited := false
 	for s.Priority != s.ExecutionPriority {
 		if s.ExecutionPriority == PriorityStop {
 			panic("Bot is stopped")
 		}
 
+		waited = true
 		time.Sleep(time.Millisecond * 10)
 	}
+
+	// Game state may be completely different after a pause (e.g. the player moved the character), the caller should
+	// decide the next action based on fresh data instead of the one read before waiting
+	if waited {
+		s.RefreshGameData()
+	}
 }
 func (ctx *Context) WaitForGameToLoad() {
 	for ctx.Data.OpenMenus.LoadingScreen {
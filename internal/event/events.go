@@ -14,6 +14,10 @@ const (
 	InteractionTypeEntrance InteractionType = "entrance"
 	InteractionTypeNPC      InteractionType = "npc"
 	InteractionTypeObject   InteractionType = "object"
+
+	DrainStarted  DrainStatus = "started"
+	DrainFinished DrainStatus = "finished"
+	DrainTimedOut DrainStatus = "timed out"
 )
 
 type UsedPotionEvent struct {
@@ -82,6 +86,18 @@ func ItemStashed(be BaseEvent, drop data.Drop) ItemStashedEvent {
 	}
 }
 
+type ItemPickedUpEvent struct {
+	BaseEvent
+	Item data.Item
+}
+
+func ItemPickedUp(be BaseEvent, i data.Item) ItemPickedUpEvent {
+	return ItemPickedUpEvent{
+		BaseEvent: be,
+		Item:      i,
+	}
+}
+
 type RunStartedEvent struct {
 	BaseEvent
 	RunName string
@@ -139,3 +155,15 @@ func GamePaused(be BaseEvent, paused bool) GamePausedEvent {
 		Paused:    paused,
 	}
 }
+
+type SupervisorDrainEvent struct {
+	BaseEvent
+	Status DrainStatus
+}
+
+func SupervisorDrain(be BaseEvent, status DrainStatus) SupervisorDrainEvent {
+	return SupervisorDrainEvent{
+		BaseEvent: be,
+		Status:    status,
+	}
+}
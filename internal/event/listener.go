@@ -2,15 +2,11 @@ package event
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"math"
 	"math/rand"
-	"os"
-	"time"
 
 	"github.com/hectorgimenez/koolo/internal/config"
-	"github.com/hectorgimenez/koolo/internal/utils"
 )
 
 var events = make(chan Event)
@@ -38,17 +34,8 @@ func (l *Listener) Listen(ctx context.Context) error {
 	for {
 		select {
 		case e := <-events:
-			if _, err := os.Stat("screenshots"); os.IsNotExist(err) {
-				err = os.MkdirAll("screenshots", os.ModePerm)
-				if err != nil {
-					l.logger.Error("error creating screenshots directory", slog.Any("error", err))
-				}
-			}
-
 			if e.Image() != nil && config.Koolo.Debug.Screenshots {
-				fileName := fmt.Sprintf("screenshots/error-%s.jpeg", time.Now().Format("2006-01-02 15_04_05"))
-				err := utils.SaveImageJPEG(e.Image(), fileName)
-				if err != nil {
+				if err := saveScreenshot(e); err != nil {
 					l.logger.Error("error saving screenshot", slog.Any("error", err))
 				}
 			}
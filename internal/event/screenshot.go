@@ -0,0 +1,95 @@
+package event
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/utils"
+)
+
+const defaultMaxScreenshots = 50
+
+// ScreenshotsDir returns the folder where the event screenshots for the supervisor are stored
+func ScreenshotsDir(supervisor string) string {
+	logDir := config.Koolo.LogSaveDirectory
+	if logDir == "" {
+		logDir = "logs"
+	}
+
+	return filepath.Join(logDir, "screenshots", supervisor)
+}
+
+// ListScreenshots returns the stored screenshot file names for the supervisor, newest first
+func ListScreenshots(supervisor string) ([]string, error) {
+	entries, err := os.ReadDir(ScreenshotsDir(supervisor))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	files := make([]string, 0)
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jpeg") {
+			files = append(files, e.Name())
+		}
+	}
+
+	// File names start with the capture time, so sorting them by name sorts them by date
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+
+	return files, nil
+}
+
+func saveScreenshot(e Event) error {
+	dir := ScreenshotsDir(e.Supervisor())
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating screenshots directory: %w", err)
+	}
+
+	// Milliseconds avoid overwriting screenshots from events happening in the same second
+	fileName := fmt.Sprintf("%s-%s.jpeg", e.OccurredAt().Format("2006-01-02 15_04_05.000"), screenshotLabel(e))
+	if err := utils.SaveImageJPEG(e.Image(), filepath.Join(dir, fileName)); err != nil {
+		return err
+	}
+
+	return removeExceedingScreenshots(e.Supervisor())
+}
+
+func removeExceedingScreenshots(supervisor string) error {
+	maxScreenshots := config.Koolo.Debug.MaxScreenshots
+	if maxScreenshots <= 0 {
+		maxScreenshots = defaultMaxScreenshots
+	}
+
+	files, err := ListScreenshots(supervisor)
+	if err != nil {
+		return err
+	}
+
+	if len(files) <= maxScreenshots {
+		return nil
+	}
+
+	for _, f := range files[maxScreenshots:] {
+		os.Remove(filepath.Join(ScreenshotsDir(supervisor), f))
+	}
+
+	return nil
+}
+
+func screenshotLabel(e Event) string {
+	switch evt := e.(type) {
+	case GameFinishedEvent:
+		return strings.ReplaceAll(string(evt.Reason), " ", "_")
+	case ItemStashedEvent:
+		return "drop"
+	}
+
+	return "event"
+}
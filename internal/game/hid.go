@@ -1,13 +1,63 @@
 package game
 
+import "sync"
+
 type HID struct {
 	gr *MemoryReader
 	gi *MemoryInjector
+	// Input is blocked while paused, pausing waits for the input being sent at that moment
+	mu      sync.Mutex
+	cond    *sync.Cond
+	paused  bool
+	sending int
 }
 
 func NewHID(gr *MemoryReader, gi *MemoryInjector) *HID {
-	return &HID{
+	hid := &HID{
 		gr: gr,
 		gi: gi,
 	}
+	hid.cond = sync.NewCond(&hid.mu)
+
+	return hid
+}
+
+// Pause blocks any keyboard or mouse input sent to the game until Resume is called, so the player can take control
+// of the game window without the bot fighting for it. It returns once the input being sent is finished.
+func (hid *HID) Pause() {
+	hid.mu.Lock()
+	defer hid.mu.Unlock()
+
+	hid.paused = true
+	for hid.sending > 0 {
+		hid.cond.Wait()
+	}
+}
+
+// Resume sends the input blocked while paused
+func (hid *HID) Resume() {
+	hid.mu.Lock()
+	defer hid.mu.Unlock()
+
+	hid.paused = false
+	hid.cond.Broadcast()
+}
+
+// startInput waits until the HID is resumed, every call must be followed by finishInput
+func (hid *HID) startInput() {
+	hid.mu.Lock()
+	defer hid.mu.Unlock()
+
+	for hid.paused {
+		hid.cond.Wait()
+	}
+	hid.sending++
+}
+
+func (hid *HID) finishInput() {
+	hid.mu.Lock()
+	defer hid.mu.Unlock()
+
+	hid.sending--
+	hid.cond.Broadcast()
 }
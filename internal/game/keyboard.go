@@ -17,6 +17,13 @@ const (
 
 // PressKey receives an ASCII code and sends a key press event to the game window
 func (hid *HID) PressKey(key byte) {
+	hid.startInput()
+	defer hid.finishInput()
+
+	hid.pressKey(key)
+}
+
+func (hid *HID) pressKey(key byte) {
 	win.PostMessage(hid.gr.HWND, win.WM_KEYDOWN, uintptr(key), hid.calculatelParam(key, true))
 	sleepTime := rand.Intn(keyPressMaxTime-keyPressMinTime) + keyPressMinTime
 	time.Sleep(time.Duration(sleepTime) * time.Millisecond)
@@ -32,8 +39,11 @@ func (hid *HID) KeySequence(keysToPress ...byte) {
 
 // PressKeyWithModifier works the same as PressKey but with a modifier key (shift, ctrl, alt)
 func (hid *HID) PressKeyWithModifier(key byte, modifier ModifierKey) {
+	hid.startInput()
+	defer hid.finishInput()
+
 	hid.gi.OverrideGetKeyState(byte(modifier))
-	hid.PressKey(key)
+	hid.pressKey(key)
 	hid.gi.RestoreGetKeyState()
 }
 
@@ -49,11 +59,15 @@ func (hid *HID) PressKeyBinding(kb data.KeyBinding) {
 
 // KeyDown sends a key down event to the game window
 func (hid *HID) KeyDown(kb data.KeyBinding) {
+	hid.startInput()
+	defer hid.finishInput()
+
 	keys := getKeysForKB(kb)
 	win.PostMessage(hid.gr.HWND, win.WM_KEYDOWN, uintptr(keys[0]), hid.calculatelParam(keys[0], true))
 }
 
-// KeyUp sends a key up event to the game window
+// KeyUp sends a key up event to the game window, it's never blocked by a pause so no key is left pressed while the
+// player has the control
 func (hid *HID) KeyUp(kb data.KeyBinding) {
 	keys := getKeysForKB(kb)
 	win.PostMessage(hid.gr.HWND, win.WM_KEYUP, uintptr(keys[0]), hid.calculatelParam(keys[0], false))
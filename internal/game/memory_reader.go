@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"strconv"
 	"sync"
 	"time"
@@ -119,10 +120,14 @@ func (gd *MemoryReader) updateWindowPositionData() {
 	win.ClientToScreen(gd.HWND, &point)
 	win.GetWindowPlacement(gd.HWND, &pos)
 
+	// Koolo is per monitor DPI aware, so the window metrics are physical pixels and the window border is scaled by
+	// the DPI of the monitor where the game window is placed
+	border := int(math.Round(9 * config.GetDisplayScaleForWindow(gd.HWND)))
+
 	gd.WindowLeftX = int(point.X)
 	gd.WindowTopY = int(point.Y)
-	gd.GameAreaSizeX = int(pos.RcNormalPosition.Right) - gd.WindowLeftX - 9
-	gd.GameAreaSizeY = int(pos.RcNormalPosition.Bottom) - gd.WindowTopY - 9
+	gd.GameAreaSizeX = int(pos.RcNormalPosition.Right) - gd.WindowLeftX - border
+	gd.GameAreaSizeY = int(pos.RcNormalPosition.Bottom) - gd.WindowTopY - border
 }
 
 func (gd *MemoryReader) GetData() Data {
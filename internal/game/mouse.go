@@ -21,6 +21,13 @@ type ModifierKey byte
 // MovePointer moves the mouse to the requested position, x and y should be the final position based on
 // pixels shown in the screen. Top-left corner is 0,0
 func (hid *HID) MovePointer(x, y int) {
+	hid.startInput()
+	defer hid.finishInput()
+
+	hid.movePointer(x, y)
+}
+
+func (hid *HID) movePointer(x, y int) {
 	hid.gr.updateWindowPositionData()
 	x = hid.gr.WindowLeftX + x
 	y = hid.gr.WindowTopY + y
@@ -34,7 +41,14 @@ func (hid *HID) MovePointer(x, y int) {
 
 // Click just does a single mouse click at current pointer position
 func (hid *HID) Click(btn MouseButton, x, y int) {
-	hid.MovePointer(x, y)
+	hid.startInput()
+	defer hid.finishInput()
+
+	hid.click(btn, x, y)
+}
+
+func (hid *HID) click(btn MouseButton, x, y int) {
+	hid.movePointer(x, y)
 	x = hid.gr.WindowLeftX + x
 	y = hid.gr.WindowTopY + y
 
@@ -53,8 +67,11 @@ func (hid *HID) Click(btn MouseButton, x, y int) {
 }
 
 func (hid *HID) ClickWithModifier(btn MouseButton, x, y int, modifier ModifierKey) {
+	hid.startInput()
+	defer hid.finishInput()
+
 	hid.gi.OverrideGetKeyState(byte(modifier))
-	hid.Click(btn, x, y)
+	hid.click(btn, x, y)
 	hid.gi.RestoreGetKeyState()
 }
 
@@ -1,21 +1,55 @@
 package game
 
 import (
+	"errors"
 	"image"
 	"unsafe"
 
 	"github.com/hectorgimenez/koolo/internal/utils/winproc"
+	"github.com/lxn/win"
 )
 
 func (gd *MemoryReader) Screenshot() image.Image {
+	img, _ := gd.TryScreenshot()
+
+	return img
+}
+
+// TryScreenshot captures the game window, it will fail if the window is minimized or the handle is not valid anymore
+func (gd *MemoryReader) TryScreenshot() (image.Image, error) {
+	if isWindow, _, _ := winproc.IsWindow.Call(uintptr(gd.HWND)); isWindow == 0 {
+		return nil, errors.New("game window handle is not valid")
+	}
+
+	if win.IsIconic(gd.HWND) {
+		return nil, errors.New("game window is minimized")
+	}
+
+	if gd.GameAreaSizeX <= 0 || gd.GameAreaSizeY <= 0 {
+		return nil, errors.New("game window has no size")
+	}
+
 	// Create a device context compatible with the window
 	hdcWindow, _, _ := winproc.GetWindowDC.Call(uintptr(gd.HWND))
+	if hdcWindow == 0 {
+		return nil, errors.New("error getting game window device context")
+	}
+	defer win.ReleaseDC(gd.HWND, win.HDC(hdcWindow))
+
 	hdcMem, _, _ := winproc.CreateCompatibleDC.Call(hdcWindow)
 	hbmMem, _, _ := winproc.CreateCompatibleBitmap.Call(hdcWindow, uintptr(gd.GameAreaSizeX), uintptr(gd.GameAreaSizeY))
 	_, _, _ = winproc.SelectObject.Call(hdcMem, hbmMem)
 
+	// Cleanup
+	defer func() {
+		_, _, _ = winproc.DeleteObject.Call(hbmMem)
+		_, _, _ = winproc.DeleteDC.Call(hdcMem)
+	}()
+
 	// Use PrintWindow to copy the window into the bitmap
-	winproc.PrintWindow.Call(uintptr(gd.HWND), hdcMem, 3) // use 3 to get window content only
+	if printed, _, _ := winproc.PrintWindow.Call(uintptr(gd.HWND), hdcMem, 3); printed == 0 { // use 3 to get window content only
+		return nil, errors.New("error copying game window content")
+	}
 
 	// map the bitmap structure
 	bmpInfo := struct {
@@ -41,7 +75,7 @@ func (gd *MemoryReader) Screenshot() image.Image {
 
 	bufSize := gd.GameAreaSizeX * gd.GameAreaSizeY * 4
 	buf := make([]byte, bufSize)
-	winproc.GetDIBits.Call(
+	lines, _, _ := winproc.GetDIBits.Call(
 		hdcMem,
 		hbmMem,
 		0,
@@ -50,6 +84,9 @@ func (gd *MemoryReader) Screenshot() image.Image {
 		uintptr(unsafe.Pointer(&bmpInfo)),
 		0, // DIB_RGB_COLORS
 	)
+	if lines == 0 {
+		return nil, errors.New("error reading game window bitmap")
+	}
 
 	// Convert raw bytes to *image.RGBA
 	img := image.NewRGBA(image.Rect(0, 0, gd.GameAreaSizeX, gd.GameAreaSizeY))
@@ -64,9 +101,5 @@ func (gd *MemoryReader) Screenshot() image.Image {
 		}
 	}
 
-	// Cleanup
-	_, _, _ = winproc.DeleteObject.Call(hbmMem)
-	_, _, _ = winproc.DeleteDC.Call(hdcMem)
-
-	return img
+	return img, nil
 }
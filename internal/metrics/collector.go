@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+const (
+	StateRunning = "running"
+	StatePaused  = "paused"
+	StateStopped = "stopped"
+)
+
+// Upper bounds in seconds for the run duration histogram
+var runDurationBuckets = []float64{30, 60, 120, 180, 300, 600, 900, 1800}
+
+// Collector aggregates supervisor telemetry from the events. Counters are kept per supervisor name for the whole
+// process lifetime, so stopping and starting a supervisor doesn't reset them.
+type Collector struct {
+	mu          sync.Mutex
+	supervisors map[string]*supervisorMetrics
+}
+
+type supervisorMetrics struct {
+	gamesStarted  float64
+	gamesFinished map[event.FinishReason]float64
+	chickens      float64
+	deaths        float64
+	errors        float64
+	itemsPickedUp float64
+	itemsStashed  float64
+
+	inGameSeconds float64
+	inMenuSeconds float64
+	gameStartedAt time.Time
+	gameEndedAt   time.Time
+
+	runStartedAt map[string]time.Time
+	runDurations map[string]*histogram
+}
+
+type histogram struct {
+	buckets []float64
+	count   float64
+	sum     float64
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		supervisors: make(map[string]*supervisorMetrics),
+	}
+}
+
+func (c *Collector) Handle(_ context.Context, e event.Event) error {
+	if e.Supervisor() == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sm := c.supervisor(e.Supervisor())
+	switch evt := e.(type) {
+	case event.GameCreatedEvent:
+		sm.gamesStarted++
+		if !sm.gameEndedAt.IsZero() {
+			sm.inMenuSeconds += evt.OccurredAt().Sub(sm.gameEndedAt).Seconds()
+			sm.gameEndedAt = time.Time{}
+		}
+		sm.gameStartedAt = evt.OccurredAt()
+
+	case event.GameFinishedEvent:
+		sm.gamesFinished[evt.Reason]++
+		switch evt.Reason {
+		case event.FinishedChicken, event.FinishedMercChicken:
+			sm.chickens++
+		case event.FinishedDied:
+			sm.deaths++
+		case event.FinishedError:
+			sm.errors++
+		}
+		if !sm.gameStartedAt.IsZero() {
+			sm.inGameSeconds += evt.OccurredAt().Sub(sm.gameStartedAt).Seconds()
+			sm.gameStartedAt = time.Time{}
+		}
+		sm.gameEndedAt = evt.OccurredAt()
+
+	case event.RunStartedEvent:
+		sm.runStartedAt[evt.RunName] = evt.OccurredAt()
+
+	case event.RunFinishedEvent:
+		startedAt, found := sm.runStartedAt[evt.RunName]
+		if !found {
+			return nil
+		}
+		delete(sm.runStartedAt, evt.RunName)
+
+		h, found := sm.runDurations[evt.RunName]
+		if !found {
+			h = &histogram{buckets: make([]float64, len(runDurationBuckets))}
+			sm.runDurations[evt.RunName] = h
+		}
+		h.observe(evt.OccurredAt().Sub(startedAt).Seconds())
+
+	case event.ItemPickedUpEvent:
+		sm.itemsPickedUp++
+
+	case event.ItemStashedEvent:
+		sm.itemsStashed++
+	}
+
+	return nil
+}
+
+func (c *Collector) supervisor(name string) *supervisorMetrics {
+	sm, found := c.supervisors[name]
+	if !found {
+		sm = &supervisorMetrics{
+			gamesFinished: make(map[event.FinishReason]float64),
+			runStartedAt:  make(map[string]time.Time),
+			runDurations:  make(map[string]*histogram),
+		}
+		c.supervisors[name] = sm
+	}
+
+	return sm
+}
+
+func (h *histogram) observe(value float64) {
+	h.count++
+	h.sum += value
+	for i, upperBound := range runDurationBuckets {
+		if value <= upperBound {
+			h.buckets[i]++
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+// Write renders all the metrics using the Prometheus text exposition format, states contains the current state
+// (running, paused or stopped) for every known supervisor
+func (c *Collector) Write(w io.Writer, states map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for name, state := range states {
+		c.supervisor(name).updateClocks(state, now)
+	}
+
+	names := make([]string, 0, len(c.supervisors))
+	for name := range c.supervisors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+
+	writeHeader(bw, "koolo_games_started_total", "counter", "Total number of games started.")
+	for _, name := range names {
+		writeSample(bw, "koolo_games_started_total", c.supervisors[name].gamesStarted, "supervisor", name)
+	}
+
+	writeHeader(bw, "koolo_games_finished_total", "counter", "Total number of games finished, by finish reason.")
+	for _, name := range names {
+		sm := c.supervisors[name]
+		reasons := make([]string, 0, len(sm.gamesFinished))
+		for reason := range sm.gamesFinished {
+			reasons = append(reasons, string(reason))
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			writeSample(bw, "koolo_games_finished_total", sm.gamesFinished[event.FinishReason(reason)], "supervisor", name, "reason", reason)
+		}
+	}
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(sm *supervisorMetrics) float64
+	}{
+		{"koolo_chickens_total", "Total number of games finished by chicken, including merc chicken.", func(sm *supervisorMetrics) float64 { return sm.chickens }},
+		{"koolo_deaths_total", "Total number of games finished by character death.", func(sm *supervisorMetrics) float64 { return sm.deaths }},
+		{"koolo_errors_total", "Total number of games finished with errors.", func(sm *supervisorMetrics) float64 { return sm.errors }},
+		{"koolo_items_picked_up_total", "Total number of items picked up.", func(sm *supervisorMetrics) float64 { return sm.itemsPickedUp }},
+		{"koolo_items_stashed_total", "Total number of items stashed.", func(sm *supervisorMetrics) float64 { return sm.itemsStashed }},
+		{"koolo_in_game_seconds_total", "Total time spent in game.", func(sm *supervisorMetrics) float64 { return sm.inGameSeconds }},
+		{"koolo_in_menus_seconds_total", "Total time spent in menus between games.", func(sm *supervisorMetrics) float64 { return sm.inMenuSeconds }},
+	}
+	for _, counter := range counters {
+		writeHeader(bw, counter.name, "counter", counter.help)
+		for _, name := range names {
+			writeSample(bw, counter.name, counter.value(c.supervisors[name]), "supervisor", name)
+		}
+	}
+
+	writeHeader(bw, "koolo_supervisor_state", "gauge", "Current supervisor state, 1 for the active state.")
+	for _, name := range names {
+		state, found := states[name]
+		if !found {
+			state = StateStopped
+		}
+		for _, st := range []string{StateRunning, StatePaused, StateStopped} {
+			value := 0.0
+			if st == state {
+				value = 1
+			}
+			writeSample(bw, "koolo_supervisor_state", value, "supervisor", name, "state", st)
+		}
+	}
+
+	writeHeader(bw, "koolo_run_duration_seconds", "histogram", "Run duration, by run type.")
+	for _, name := range names {
+		sm := c.supervisors[name]
+		runs := make([]string, 0, len(sm.runDurations))
+		for run := range sm.runDurations {
+			runs = append(runs, run)
+		}
+		sort.Strings(runs)
+		for _, run := range runs {
+			h := sm.runDurations[run]
+			for i, upperBound := range runDurationBuckets {
+				writeSample(bw, "koolo_run_duration_seconds_bucket", h.buckets[i], "supervisor", name, "run", run, "le", formatFloat(upperBound))
+			}
+			writeSample(bw, "koolo_run_duration_seconds_bucket", h.count, "supervisor", name, "run", run, "le", "+Inf")
+			writeSample(bw, "koolo_run_duration_seconds_sum", h.sum, "supervisor", name, "run", run)
+			writeSample(bw, "koolo_run_duration_seconds_count", h.count, "supervisor", name, "run", run)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// updateClocks moves the in game / in menus time up to now, clocks are stopped while the supervisor is not running
+func (sm *supervisorMetrics) updateClocks(state string, now time.Time) {
+	if !sm.gameStartedAt.IsZero() {
+		sm.inGameSeconds += now.Sub(sm.gameStartedAt).Seconds()
+		sm.gameStartedAt = now
+	}
+	if !sm.gameEndedAt.IsZero() {
+		sm.inMenuSeconds += now.Sub(sm.gameEndedAt).Seconds()
+		sm.gameEndedAt = now
+	}
+
+	if state == StateStopped {
+		sm.gameStartedAt = time.Time{}
+		sm.gameEndedAt = time.Time{}
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// writeSample writes a single sample line, labels are given as consecutive name/value pairs
+func writeSample(w *bufio.Writer, name string, value float64, labels ...string) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", labels[i], escapeLabelValue(labels[i+1]))
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -3,6 +3,7 @@ package discord
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"slices"
 	"strings"
 
@@ -15,9 +16,12 @@ type Bot struct {
 	discordSession *discordgo.Session
 	channelID      string
 	manager        *bot.SupervisorManager
+	logger         *slog.Logger
+	queue          *messageQueue
+	summary        *runSummary
 }
 
-func NewBot(token, channelID string, manager *bot.SupervisorManager) (*Bot, error) {
+func NewBot(token, channelID string, manager *bot.SupervisorManager, logger *slog.Logger) (*Bot, error) {
 	dg, err := discordgo.New("Bot " + token)
 	if err != nil {
 		return nil, fmt.Errorf("error creating Discord session: %w", err)
@@ -27,6 +31,9 @@ func NewBot(token, channelID string, manager *bot.SupervisorManager) (*Bot, erro
 		discordSession: dg,
 		channelID:      channelID,
 		manager:        manager,
+		logger:         logger,
+		queue:          newMessageQueue(),
+		summary:        newRunSummary(),
 	}, nil
 }
 
@@ -39,6 +46,9 @@ func (b *Bot) Start(ctx context.Context) error {
 		return fmt.Errorf("error opening connection: %w", err)
 	}
 
+	go b.sendQueuedMessages(ctx)
+	go b.sendSummaryPeriodically(ctx)
+
 	// Wait until context is finished
 	<-ctx.Done()
 
@@ -1,38 +1,57 @@
 package discord
 
 import (
-	"bytes"
 	"context"
-	"image/jpeg"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/item"
 	"github.com/hectorgimenez/koolo/internal/config"
 	"github.com/hectorgimenez/koolo/internal/event"
 )
 
-func (b *Bot) Handle(_ context.Context, e event.Event) error {
-	if b.shouldPublish(e) {
+const runeColor = 0xFFA800
 
-		switch e.(type) {
-		case event.GameCreatedEvent, event.GameFinishedEvent, event.RunStartedEvent, event.RunFinishedEvent:
-			_, err := b.discordSession.ChannelMessageSend(b.channelID, e.Message())
-			return err
-		default:
-			break
-		}
+var dropQualities = map[string]item.Quality{
+	"lowquality": item.QualityLowQuality,
+	"normal":     item.QualityNormal,
+	"superior":   item.QualitySuperior,
+	"magic":      item.QualityMagic,
+	"set":        item.QualitySet,
+	"rare":       item.QualityRare,
+	"unique":     item.QualityUnique,
+	"crafted":    item.QualityCrafted,
+}
 
-		buf := new(bytes.Buffer)
-		err := jpeg.Encode(buf, e.Image(), &jpeg.Options{Quality: 80})
-		if err != nil {
-			return err
-		}
+// Same colors used by the game for the item names
+var qualityColors = map[item.Quality]int{
+	item.QualityLowQuality: 0x9D9D9D,
+	item.QualityNormal:     0xFFFFFF,
+	item.QualitySuperior:   0xFFFFFF,
+	item.QualityMagic:      0x6969FF,
+	item.QualitySet:        0x00FF00,
+	item.QualityRare:       0xFFFF64,
+	item.QualityUnique:     0xC7B377,
+	item.QualityCrafted:    0xFFA800,
+}
 
-		_, err = b.discordSession.ChannelMessageSendComplex(b.channelID, &discordgo.MessageSend{
-			File:    &discordgo.File{Name: "Screenshot.jpeg", ContentType: "image/jpeg", Reader: buf},
-			Content: e.Message(),
-		})
+// Handle queues the messages for the event, sending them is up to the bot so Discord never blocks the event listener
+func (b *Bot) Handle(_ context.Context, e event.Event) error {
+	if config.Koolo.Discord.EnableRunSummaryMessages && b.summary.handle(e) {
+		b.queue.push(b.summary.flush())
+	}
 
-		return err
+	if evt, ok := e.(event.ItemStashedEvent); ok {
+		if shouldPublishDrop(evt.Item) {
+			b.queue.push(dropMessage(evt))
+		}
+		return nil
+	}
+
+	if b.shouldPublish(e) {
+		b.queue.push(message{content: e.Message(), screenshot: screenshot(e.Image())})
 	}
 
 	return nil
@@ -42,22 +61,86 @@ func (b *Bot) shouldPublish(e event.Event) bool {
 
 	switch evt := e.(type) {
 	case event.GameFinishedEvent:
-		if evt.Reason == event.FinishedChicken || evt.Reason == event.FinishedMercChicken || evt.Reason == event.FinishedDied {
+		switch evt.Reason {
+		case event.FinishedChicken, event.FinishedMercChicken:
 			return config.Koolo.Discord.EnableDiscordChickenMessages
+		case event.FinishedDied:
+			return config.Koolo.Discord.EnableDeathMessages
+		case event.FinishedError:
+			return config.Koolo.Discord.EnableErrorMessages
 		}
-		if evt.Reason == event.FinishedOK {
-			return false // supress game finished messages until we add proper option for it
-		}
-		return true
+		return false // game finished messages are covered by the run summary
 	case event.GameCreatedEvent:
 		return config.Koolo.Discord.EnableGameCreatedMessages
 	case event.RunStartedEvent:
 		return config.Koolo.Discord.EnableNewRunMessages
 	case event.RunFinishedEvent:
 		return config.Koolo.Discord.EnableRunFinishMessages
+	case event.GamePausedEvent:
+		return true
 	default:
 		break
 	}
 
 	return e.Image() != nil
 }
+
+func shouldPublishDrop(drop data.Drop) bool {
+	if !config.Koolo.Discord.EnableDropMessages {
+		return false
+	}
+
+	// Runes and runewords are normal quality items, but they are usually the most valuable ones
+	if drop.Item.IsRuneword || drop.Item.Type().IsType(item.TypeRune) {
+		return true
+	}
+
+	// No filter configured, everything is posted
+	if len(config.Koolo.Discord.DropQualities) == 0 {
+		return true
+	}
+
+	for _, q := range config.Koolo.Discord.DropQualities {
+		if quality, found := dropQualities[strings.ToLower(q)]; found && quality == drop.Item.Quality {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dropMessage(evt event.ItemStashedEvent) message {
+	i := evt.Item.Item
+
+	color := qualityColors[i.Quality]
+	if i.Type().IsType(item.TypeRune) {
+		color = runeColor
+	}
+
+	description := i.Quality.ToString()
+	if i.Ethereal {
+		description += ", Ethereal"
+	}
+
+	run := evt.Item.DropLocation
+	if run == "" {
+		run = "Unknown"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       string(i.Name),
+		Description: description,
+		Color:       color,
+		Timestamp:   evt.OccurredAt().Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Supervisor", Value: evt.Supervisor(), Inline: true},
+			{Name: "Run", Value: run, Inline: true},
+		},
+	}
+
+	if evt.Item.Rule != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: evt.Item.Rule}
+	}
+
+	return message{embed: embed, screenshot: screenshot(evt.Image()), drop: true}
+}
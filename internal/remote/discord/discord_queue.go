@@ -0,0 +1,168 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	screenshotFileName = "Screenshot.jpeg"
+	maxSendAttempts    = 3
+	sendRetryDelay     = 5 * time.Second
+	// Messages keep piling up while Discord is rate limiting or unreachable, the oldest ones are discarded over this limit
+	maxQueuedMessages = 100
+)
+
+type message struct {
+	content string
+	embed   *discordgo.MessageEmbed
+	// screenshot is already JPEG encoded, the raw images are too big to be kept in the queue
+	screenshot []byte
+	// drop messages are the last ones to be discarded when the queue is full
+	drop bool
+}
+
+// messageQueue keeps the messages pending to be sent, so the event handler never blocks waiting for Discord
+type messageQueue struct {
+	mu      sync.Mutex
+	pending []message
+	notify  chan struct{}
+}
+
+func newMessageQueue() *messageQueue {
+	return &messageQueue{
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (q *messageQueue) push(msg message) {
+	q.mu.Lock()
+	if len(q.pending) >= maxQueuedMessages {
+		q.discardOldest()
+	}
+	q.pending = append(q.pending, msg)
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *messageQueue) peek() (message, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return message{}, false
+	}
+
+	return q.pending[0], true
+}
+
+func (q *messageQueue) pop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) > 0 {
+		q.pending[0] = message{}
+		q.pending = q.pending[1:]
+	}
+}
+
+// discardOldest removes the oldest message that is not a drop, or the oldest drop when all of them are drops. The
+// first message is never removed, it may be the one being sent.
+func (q *messageQueue) discardOldest() {
+	discard := 1
+	for i := 1; i < len(q.pending); i++ {
+		if !q.pending[i].drop {
+			discard = i
+			break
+		}
+	}
+
+	q.pending = slices.Delete(q.pending, discard, discard+1)
+}
+
+// screenshot encodes the image to be sent with a message, the message is sent without image if it can't be encoded
+func screenshot(img image.Image) []byte {
+	if img == nil {
+		return nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 80}); err != nil {
+		return nil
+	}
+
+	return buf.Bytes()
+}
+
+// sendQueuedMessages sends the queued messages in order until the context is done. Rate limited messages are kept
+// in the queue and sent again once Discord allows it.
+func (b *Bot) sendQueuedMessages(ctx context.Context) {
+	attempts := 0
+	for {
+		msg, found := b.queue.peek()
+		if !found {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.queue.notify:
+			}
+			continue
+		}
+
+		err := b.send(msg)
+		if err == nil {
+			attempts = 0
+			b.queue.pop()
+			continue
+		}
+
+		wait := sendRetryDelay
+		var rateLimitErr *discordgo.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			wait = rateLimitErr.RetryAfter
+		} else if attempts++; attempts >= maxSendAttempts {
+			b.logger.Error("Discord message could not be sent, discarding it", slog.Any("error", err))
+			attempts = 0
+			b.queue.pop()
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (b *Bot) send(msg message) error {
+	data := &discordgo.MessageSend{Content: msg.content}
+	if msg.embed != nil {
+		data.Embeds = []*discordgo.MessageEmbed{msg.embed}
+	}
+
+	if msg.screenshot != nil {
+		// New reader on every attempt, it's consumed by each request
+		data.Files = []*discordgo.File{{Name: screenshotFileName, ContentType: "image/jpeg", Reader: bytes.NewReader(msg.screenshot)}}
+		if msg.embed != nil {
+			msg.embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + screenshotFileName}
+		}
+	}
+
+	_, err := b.discordSession.ChannelMessageSendComplex(b.channelID, data, discordgo.WithRetryOnRatelimit(false))
+
+	return err
+}
@@ -0,0 +1,197 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+const (
+	summaryColor       = 0x5865F2
+	maxEmbedFieldValue = 1024
+)
+
+// runSummary aggregates the game results from all the supervisors between two summary messages
+type runSummary struct {
+	mu          sync.Mutex
+	since       time.Time
+	games       int
+	supervisors map[string]*supervisorSummary
+}
+
+type supervisorSummary struct {
+	games        map[event.FinishReason]int
+	drops        int
+	runs         map[string]*runTotals
+	runStartedAt time.Time
+}
+
+type runTotals struct {
+	count    int
+	failed   int
+	duration time.Duration
+}
+
+func newRunSummary() *runSummary {
+	return &runSummary{
+		since:       time.Now(),
+		supervisors: make(map[string]*supervisorSummary),
+	}
+}
+
+// handle adds the event to the summary, returns true when enough games are collected to send it
+func (rs *runSummary) handle(e event.Event) bool {
+	if e.Supervisor() == "" {
+		return false
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	ss, found := rs.supervisors[e.Supervisor()]
+	if !found {
+		ss = &supervisorSummary{
+			games: make(map[event.FinishReason]int),
+			runs:  make(map[string]*runTotals),
+		}
+		rs.supervisors[e.Supervisor()] = ss
+	}
+
+	switch evt := e.(type) {
+	case event.RunStartedEvent:
+		ss.runStartedAt = evt.OccurredAt()
+	case event.RunFinishedEvent:
+		rt, found := ss.runs[evt.RunName]
+		if !found {
+			rt = &runTotals{}
+			ss.runs[evt.RunName] = rt
+		}
+		rt.count++
+		if evt.Reason != event.FinishedOK {
+			rt.failed++
+		}
+		if !ss.runStartedAt.IsZero() {
+			rt.duration += evt.OccurredAt().Sub(ss.runStartedAt)
+			ss.runStartedAt = time.Time{}
+		}
+	case event.ItemStashedEvent:
+		ss.drops++
+	case event.GameFinishedEvent:
+		ss.games[evt.Reason]++
+		rs.games++
+
+		return config.Koolo.Discord.RunSummaryGames > 0 && rs.games >= config.Koolo.Discord.RunSummaryGames
+	}
+
+	return false
+}
+
+// due returns true when the summary interval is over, empty periods are skipped without sending anything
+func (rs *runSummary) due() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	interval := time.Duration(config.Koolo.Discord.RunSummaryMinutes) * time.Minute
+	if interval <= 0 || time.Since(rs.since) < interval {
+		return false
+	}
+
+	if rs.games == 0 {
+		rs.since = time.Now()
+		return false
+	}
+
+	return true
+}
+
+// flush builds the summary message and starts a new summary period
+func (rs *runSummary) flush() message {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	names := make([]string, 0, len(rs.supervisors))
+	for name := range rs.supervisors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Run summary, %d games", rs.games),
+		Description: fmt.Sprintf("Since %s", rs.since.Format(time.TimeOnly)),
+		Color:       summaryColor,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	for _, name := range names {
+		ss := rs.supervisors[name]
+		games := 0
+		for _, count := range ss.games {
+			games += count
+		}
+		if games == 0 && len(ss.runs) == 0 {
+			continue
+		}
+
+		lines := []string{
+			fmt.Sprintf("Games: %d (%d ok, %d chicken, %d death, %d error)",
+				games,
+				ss.games[event.FinishedOK],
+				ss.games[event.FinishedChicken]+ss.games[event.FinishedMercChicken],
+				ss.games[event.FinishedDied],
+				ss.games[event.FinishedError],
+			),
+			fmt.Sprintf("Drops: %d", ss.drops),
+		}
+
+		runNames := make([]string, 0, len(ss.runs))
+		for runName := range ss.runs {
+			runNames = append(runNames, runName)
+		}
+		sort.Strings(runNames)
+		for _, runName := range runNames {
+			rt := ss.runs[runName]
+			lines = append(lines, fmt.Sprintf("%s: %d runs, %d failed, avg %s",
+				runName, rt.count, rt.failed, (rt.duration/time.Duration(rt.count)).Round(time.Second)))
+		}
+
+		value := strings.Join(lines, "\n")
+		if len(value) > maxEmbedFieldValue {
+			value = value[:maxEmbedFieldValue-3] + "..."
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	rs.since = time.Now()
+	rs.games = 0
+	rs.supervisors = make(map[string]*supervisorSummary)
+
+	return message{embed: embed}
+}
+
+// sendSummaryPeriodically queues the summary once the configured interval is over
+func (b *Bot) sendSummaryPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if config.Koolo.Discord.EnableRunSummaryMessages && b.summary.due() {
+				b.queue.push(b.summary.flush())
+			}
+		}
+	}
+}
@@ -3,31 +3,40 @@ package telegram
 import (
 	"context"
 	"log/slog"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/koolo/internal/bot"
 )
 
 type Bot struct {
-	bot    *tgbotapi.BotAPI
-	chatID int64
-	logger *slog.Logger
+	bot     *tgbotapi.BotAPI
+	chatID  int64
+	manager *bot.SupervisorManager
+	logger  *slog.Logger
+	// Only accessed by the event handler, events are handled one by one
+	runs  map[string]*runProgress
+	drops map[string][]data.Drop
 }
 
-func NewBot(token string, chatID int64, logger *slog.Logger) (*Bot, error) {
-	bot, err := tgbotapi.NewBotAPI(token)
+func NewBot(token string, chatID int64, manager *bot.SupervisorManager, logger *slog.Logger) (*Bot, error) {
+	tgBot, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Bot{
-		bot:    bot,
-		chatID: chatID,
-		logger: logger,
+		bot:     tgBot,
+		chatID:  chatID,
+		manager: manager,
+		logger:  logger,
+		runs:    make(map[string]*runProgress),
+		drops:   make(map[string][]data.Drop),
 	}, nil
 }
 
-func (b *Bot) Start(_ context.Context) error {
+// Start polls the Telegram updates handling the commands and buttons until the context is done
+func (b *Bot) Start(ctx context.Context) error {
 	offset, err := b.getLatestOffset()
 	if err != nil {
 		return err
@@ -36,22 +45,18 @@ func (b *Bot) Start(_ context.Context) error {
 	u := tgbotapi.NewUpdate(offset)
 	u.Timeout = 5
 	updates := b.bot.GetUpdatesChan(u)
-	for update := range updates {
-		if update.Message != nil && update.Message.Chat.ID == b.chatID { // If we got a message
-			switch strings.ToLower(update.Message.Text) {
-			case "stats":
-				if err := b.publishStats(); err != nil {
-					b.logger.Error("error sending telegram message", slog.Any("error", err))
-				}
-			case "start":
-				// TODO: Implement
-			case "stop":
-				//b.sueprvisor.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			b.bot.StopReceivingUpdates()
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
 			}
+			b.handleUpdate(update)
 		}
 	}
-
-	return nil
 }
 
 func (b *Bot) getLatestOffset() (int, error) {
@@ -69,27 +74,8 @@ func (b *Bot) getLatestOffset() (int, error) {
 	return offset, nil
 }
 
-func (b *Bot) publishStats() error {
-	return nil
-	//msg := "Run | Items | Deaths | Chickens | Merc Chickens | Errors | Healing Pots | Mana Pots | Reju Pots | Merc Pots \n"
-	//for run, st := range stat.Status.RunStats {
-	//	msg += fmt.Sprintf(
-	//		"%s | %d | %d | %d | %d | %d | %d | %d| %d | %d | %d \n",
-	//		run,
-	//		len(st.ItemsFound),
-	//		st.Kills,
-	//		st.Deaths,
-	//		st.Chickens,
-	//		st.MerChicken,
-	//		st.Errors,
-	//		st.HealingPotionsUsed,
-	//		st.ManaPotionsUsed,
-	//		st.RejuvPotionsUsed,
-	//		st.MercHealingPotionsUsed,
-	//	)
-	//}
-	//
-	//_, err := b.bot.Send(tgbotapi.NewMessage(b.chatID, msg))
-	//
-	//return err
+func (b *Bot) sendMessage(msg tgbotapi.Chattable) {
+	if _, err := b.bot.Send(msg); err != nil {
+		b.logger.Error("error sending telegram message", slog.Any("error", err))
+	}
 }
@@ -0,0 +1,174 @@
+package telegram
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/hectorgimenez/koolo/internal/bot"
+)
+
+const (
+	actionStart = "start"
+	actionStop  = "stop"
+	actionStats = "stats"
+
+	// Telegram rejects the buttons with more than 64 bytes of callback data
+	maxCallbackData = 64
+)
+
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	switch {
+	case update.Message != nil:
+		// Only the configured chat is allowed to control the bot
+		if update.Message.Chat.ID != b.chatID || !update.Message.IsCommand() {
+			return
+		}
+
+		switch update.Message.Command() {
+		case "start", "supervisors":
+			b.handleSupervisorsRequest()
+		case "status":
+			b.handleStatusRequest()
+		}
+	case update.CallbackQuery != nil:
+		if update.CallbackQuery.Message == nil || update.CallbackQuery.Message.Chat.ID != b.chatID {
+			return
+		}
+
+		b.handleButtonPressed(update.CallbackQuery)
+	}
+}
+
+// handleSupervisorsRequest replies with all the supervisors, each one with its own Start/Stop/Stats buttons
+func (b *Bot) handleSupervisorsRequest() {
+	supervisors := b.supervisors()
+	if len(supervisors) == 0 {
+		b.sendMessage(tgbotapi.NewMessage(b.chatID, "There are no supervisors configured"))
+		return
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(supervisors))
+	for _, supervisor := range supervisors {
+		if len(actionStats+":"+supervisor) > maxCallbackData {
+			b.logger.Warn("Supervisor name is too long for the Telegram buttons", slog.String("supervisor", supervisor))
+			continue
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Start "+supervisor, actionStart+":"+supervisor),
+			tgbotapi.NewInlineKeyboardButtonData("Stop", actionStop+":"+supervisor),
+			tgbotapi.NewInlineKeyboardButtonData("Stats", actionStats+":"+supervisor),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(b.chatID, b.statusText())
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.sendMessage(msg)
+}
+
+func (b *Bot) handleStatusRequest() {
+	b.sendMessage(tgbotapi.NewMessage(b.chatID, b.statusText()))
+}
+
+func (b *Bot) handleButtonPressed(query *tgbotapi.CallbackQuery) {
+	action, supervisor, _ := strings.Cut(query.Data, ":")
+
+	var reply string
+	switch {
+	case !slices.Contains(b.manager.AvailableSupervisors(), supervisor):
+		reply = fmt.Sprintf("Supervisor %s not found", supervisor)
+	case action == actionStart:
+		reply = b.startSupervisor(supervisor)
+	case action == actionStop:
+		reply = b.stopSupervisor(supervisor)
+	case action == actionStats:
+		b.sendMessage(tgbotapi.NewMessage(b.chatID, b.statsText(supervisor)))
+	default:
+		reply = "Unknown action"
+	}
+
+	// Telegram keeps showing the button as loading until the callback is answered
+	if _, err := b.bot.Request(tgbotapi.NewCallback(query.ID, reply)); err != nil {
+		b.logger.Error("error answering telegram callback", slog.Any("error", err))
+	}
+}
+
+func (b *Bot) startSupervisor(supervisor string) string {
+	if b.manager.Status(supervisor).SupervisorStatus != "" {
+		return fmt.Sprintf("Supervisor %s is already running", supervisor)
+	}
+
+	if b.manager.WaitingForTokenAuth(supervisor) {
+		return "Another client using token auth is still starting, try again later"
+	}
+
+	// Start blocks until the supervisor is stopped
+	go func() {
+		if err := b.manager.Start(supervisor, false); err != nil {
+			b.logger.Error("Failed to start supervisor from Telegram", slog.String("supervisor", supervisor), slog.Any("error", err))
+			b.sendMessage(tgbotapi.NewMessage(b.chatID, fmt.Sprintf("Supervisor %s could not be started: %s", supervisor, err.Error())))
+		}
+	}()
+
+	return fmt.Sprintf("Starting supervisor %s", supervisor)
+}
+
+func (b *Bot) stopSupervisor(supervisor string) string {
+	if b.manager.Status(supervisor).SupervisorStatus == "" {
+		return fmt.Sprintf("Supervisor %s is not running", supervisor)
+	}
+
+	b.manager.Stop(supervisor)
+
+	return fmt.Sprintf("Supervisor %s has been stopped", supervisor)
+}
+
+func (b *Bot) statusText() string {
+	lines := []string{"Supervisors:"}
+	for _, supervisor := range b.supervisors() {
+		lines = append(lines, fmt.Sprintf("%s: %s", supervisor, supervisorStatus(b.manager.Status(supervisor))))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (b *Bot) statsText(supervisor string) string {
+	stats := b.manager.GetSupervisorStats(supervisor)
+
+	uptime := "-"
+	if stats.SupervisorStatus != "" {
+		uptime = time.Since(stats.StartedAt).Round(time.Second).String()
+	}
+
+	return fmt.Sprintf(
+		"Stats for %s\nStatus: %s\nUptime: %s\nGames: %d\nDrops: %d\nDeaths: %d\nChickens: %d\nErrors: %d",
+		supervisor,
+		supervisorStatus(stats),
+		uptime,
+		stats.TotalGames(),
+		len(stats.Drops),
+		stats.TotalDeaths(),
+		stats.TotalChickens(),
+		stats.TotalErrors(),
+	)
+}
+
+func (b *Bot) supervisors() []string {
+	supervisors := b.manager.AvailableSupervisors()
+	sort.Strings(supervisors)
+
+	return supervisors
+}
+
+func supervisorStatus(stats bot.Stats) string {
+	if stats.SupervisorStatus == "" || stats.SupervisorStatus == bot.NotStarted {
+		return "Offline"
+	}
+
+	return string(stats.SupervisorStatus)
+}
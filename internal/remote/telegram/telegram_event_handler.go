@@ -3,32 +3,115 @@ package telegram
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"image"
 	"image/jpeg"
+	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/hectorgimenez/koolo/internal/event"
 )
 
+type runProgress struct {
+	name      string
+	startedAt time.Time
+}
+
+var finishReasonText = map[event.FinishReason]string{
+	event.FinishedOK:          "OK",
+	event.FinishedDied:        "Died",
+	event.FinishedChicken:     "Chicken",
+	event.FinishedMercChicken: "Merc chicken",
+	event.FinishedError:       "Error",
+}
+
+// Handle sends a compact summary after every run instead of the individual events, besides the summaries only pause,
+// drain and error screenshots are sent, the rest of the events are too noisy for a chat
 func (b *Bot) Handle(_ context.Context, e event.Event) error {
-	if e.Image() != nil {
+	switch evt := e.(type) {
+	case event.RunStartedEvent:
+		b.runs[evt.Supervisor()] = &runProgress{name: evt.RunName, startedAt: evt.OccurredAt()}
+		return nil
+	case event.RunFinishedEvent:
+		return b.sendRunSummary(evt.Supervisor(), evt.Reason, evt.OccurredAt(), nil)
+	case event.ItemStashedEvent:
+		b.drops[evt.Supervisor()] = append(b.drops[evt.Supervisor()], evt.Item)
+		return nil
+	case event.GameFinishedEvent:
+		// Chicken and death interrupt the run before it's finished, so the game result closes it
+		if _, found := b.runs[evt.Supervisor()]; found {
+			return b.sendRunSummary(evt.Supervisor(), evt.Reason, evt.OccurredAt(), evt.Image())
+		}
+		if evt.Image() == nil {
+			return nil
+		}
+	case event.GamePausedEvent, event.SupervisorDrainEvent:
+	default:
+		return nil
+	}
+
+	if e.Message() == "" {
+		return nil
+	}
+
+	return b.send(e.Message(), e.Image())
+}
+
+func (b *Bot) sendRunSummary(supervisor string, reason event.FinishReason, finishedAt time.Time, img image.Image) error {
+	run, found := b.runs[supervisor]
+	if !found {
+		return nil
+	}
+	delete(b.runs, supervisor)
+
+	// Items are stashed in town after being picked up, each one is reported with the run it was found in
+	items := make([]string, 0, len(b.drops[supervisor]))
+	for _, drop := range b.drops[supervisor] {
+		item := string(drop.Item.Name)
+		if drop.DropLocation != "" && drop.DropLocation != run.name {
+			item += fmt.Sprintf(" (%s)", drop.DropLocation)
+		}
+		items = append(items, item)
+	}
+	delete(b.drops, supervisor)
+
+	itemsText := "none"
+	if len(items) > 0 {
+		itemsText = strings.Join(items, ", ")
+	}
+
+	msg := fmt.Sprintf("%s | %s | %s | %s\nItems: %s",
+		supervisor,
+		run.name,
+		finishedAt.Sub(run.startedAt).Round(time.Second),
+		finishReasonText[reason],
+		itemsText,
+	)
+
+	return b.send(msg, img)
+}
+
+func (b *Bot) send(msg string, img image.Image) error {
+	if img != nil {
 		buf := new(bytes.Buffer)
-		err := jpeg.Encode(buf, e.Image(), nil)
+		err := jpeg.Encode(buf, img, nil)
 		if err != nil {
 			return err
 		}
 
 		photo := tgbotapi.NewPhoto(b.chatID, tgbotapi.FileBytes{
-			Name:  e.Message(),
+			Name:  msg,
 			Bytes: buf.Bytes(),
 		})
-		photo.Caption = e.Message()
+		photo.Caption = msg
 
 		_, err = b.bot.Send(photo)
 
 		return err
 	}
 
-	_, err := b.bot.Send(tgbotapi.NewMessage(b.chatID, e.Message()))
+	_, err := b.bot.Send(tgbotapi.NewMessage(b.chatID, msg))
 
 	return err
 }
@@ -0,0 +1,110 @@
+package run
+
+import (
+	"github.com/hectorgimenez/d2go/pkg/data"
+	"github.com/hectorgimenez/d2go/pkg/data/area"
+	"github.com/hectorgimenez/koolo/internal/action"
+	"github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/utils"
+)
+
+const (
+	followLeaderDistance    = 10
+	followLeaderBossFight   = 4
+	followLeaderClearRadius = 15
+)
+
+// LeaderState is what a companion follower knows about its leader
+type LeaderState struct {
+	// Name is the in game character name, used to find the leader town portal
+	Name        string
+	Area        area.ID
+	Position    data.Position
+	InTown      bool
+	InBossFight bool
+	GameName    string
+}
+
+// FollowLeader keeps a companion follower next to its leader until the leader leaves the joined game. Followers travel
+// using the leader portals, or walking to adjacent areas, and stay closer to the leader during boss fights.
+type FollowLeader struct {
+	ctx         *context.Status
+	gameName    string
+	leaderState func() (LeaderState, bool)
+}
+
+func NewFollowLeader(gameName string, leaderState func() (LeaderState, bool)) *FollowLeader {
+	return &FollowLeader{
+		ctx:         context.Get(),
+		gameName:    gameName,
+		leaderState: leaderState,
+	}
+}
+
+func (f FollowLeader) Name() string {
+	return "Follow leader"
+}
+
+func (f FollowLeader) Run() error {
+	ctx := context.Get()
+	for {
+		ctx.PauseIfNotPriority()
+
+		leader, found := f.leaderState()
+		if !found {
+			f.ctx.Logger.Info("Leader left the game, finishing run")
+			return nil
+		}
+
+		// Leader may have moved to a new game while the follower was busy, or paused after a leader crash
+		if leader.GameName != f.gameName {
+			f.ctx.Logger.Info("Leader is in another game, finishing run", "game", leader.GameName)
+			return nil
+		}
+
+		switch {
+		case leader.InTown:
+			// Leader is doing its town routine, wait for it in town
+			if !f.ctx.Data.PlayerUnit.Area.IsTown() {
+				if err := action.ReturnTown(); err != nil {
+					f.ctx.Logger.Debug("Could not return to town", "error", err)
+				}
+			}
+		case f.ctx.Data.PlayerUnit.Area.IsTown():
+			// Portal may not be opened yet, it will be tried again on next iteration
+			if err := action.UsePortalFrom(leader.Name); err != nil {
+				f.ctx.Logger.Debug("Leader portal not found yet", "error", err)
+			}
+		case f.ctx.Data.PlayerUnit.Area != leader.Area:
+			// Leader is too far to walk to it, go back to town and wait for a new portal
+			if err := action.MoveToArea(leader.Area); err != nil {
+				f.ctx.Logger.Debug("Could not follow the leader to the next area, returning to town", "error", err)
+				action.ReturnTown()
+			}
+		default:
+			f.stayWithLeader(leader)
+		}
+
+		utils.Sleep(200)
+	}
+}
+
+// stayWithLeader moves next to the leader and helps it killing the monsters around, during boss fights the follower
+// doesn't leave the leader side
+func (f FollowLeader) stayWithLeader(leader LeaderState) {
+	maxDistance := followLeaderDistance
+	if leader.InBossFight {
+		maxDistance = followLeaderBossFight
+	}
+
+	if f.ctx.PathFinder.DistanceFromMe(leader.Position) > maxDistance {
+		action.MoveToCoords(leader.Position)
+	}
+
+	if leader.InBossFight {
+		action.ClearAreaAroundPosition(leader.Position, followLeaderClearRadius, data.MonsterAnyFilter())
+		return
+	}
+
+	action.ClearAreaAroundPlayer(followLeaderClearRadius, data.MonsterAnyFilter())
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hectorgimenez/koolo/internal/bot"
+	"github.com/hectorgimenez/koolo/internal/config"
+	"github.com/hectorgimenez/koolo/internal/event"
+)
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type apiSupervisor struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Games     int        `json:"games"`
+	Drops     int        `json:"drops"`
+	Deaths    int        `json:"deaths"`
+	Chickens  int        `json:"chickens"`
+	Errors    int        `json:"errors"`
+}
+
+type apiSupervisorStats struct {
+	apiSupervisor
+	Runs []apiRunStats `json:"runs"`
+}
+
+type apiRunStats struct {
+	Name                   string  `json:"name"`
+	Total                  int     `json:"total"`
+	Successful             int     `json:"successful"`
+	Deaths                 int     `json:"deaths"`
+	Chickens               int     `json:"chickens"`
+	Errors                 int     `json:"errors"`
+	AverageDurationSeconds float64 `json:"averageDurationSeconds"`
+}
+
+// apiHandler returns the REST API routes, they require the bearer token configured in koolo.yaml. The config routes
+// also accept the token given to the web UI, the settings page uses them to create, clone and delete configs.
+func (s *HttpServer) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/supervisors", s.requireAPIToken(s.apiListSupervisors))
+	mux.Handle("POST /api/supervisors/{name}/start", s.requireAPIToken(s.apiStartSupervisor))
+	mux.Handle("POST /api/supervisors/{name}/stop", s.requireAPIToken(s.apiStopSupervisor))
+	mux.Handle("GET /api/supervisors/{name}/stats", s.requireAPIToken(s.apiSupervisorStats))
+	mux.Handle("POST /api/v1/configs", s.requireUIOrAPIToken(s.apiCreateConfig))
+	mux.Handle("DELETE /api/v1/configs/{name}", s.requireUIOrAPIToken(s.apiDeleteConfig))
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, "route not found")
+	})
+
+	return mux
+}
+
+// requireAPIToken rejects the requests without the configured bearer token
+func (s *HttpServer) requireAPIToken(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// API stays disabled until a token is set, so nobody exposes the bot control by accident
+		token := config.Koolo.Server.APIToken
+		if token == "" {
+			writeAPIError(w, http.StatusForbidden, "API is disabled, set server.apiToken in koolo.yaml to enable it")
+			return
+		}
+
+		provided, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="koolo"`)
+			writeAPIError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireUIOrAPIToken accepts the token generated for the web UI on every start, besides the configured one. Other
+// sites can't read it from the UI pages, so they can't use it either.
+func (s *HttpServer) requireUIOrAPIToken(next http.HandlerFunc) http.Handler {
+	withAPIToken := s.requireAPIToken(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if found && subtle.ConstantTimeCompare([]byte(provided), []byte(s.uiToken)) == 1 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		withAPIToken.ServeHTTP(w, r)
+	})
+}
+
+func (s *HttpServer) apiListSupervisors(w http.ResponseWriter, r *http.Request) {
+	supervisors := s.manager.AvailableSupervisors()
+	sort.Strings(supervisors)
+
+	response := make([]apiSupervisor, 0, len(supervisors))
+	for _, name := range supervisors {
+		response = append(response, s.apiSupervisorSummary(name))
+	}
+
+	writeAPIResponse(w, http.StatusOK, response)
+}
+
+func (s *HttpServer) apiStartSupervisor(w http.ResponseWriter, r *http.Request) {
+	name, found := s.apiSupervisorName(w, r)
+	if !found {
+		return
+	}
+
+	if s.manager.GetSupervisorStats(name).SupervisorStatus != "" {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("supervisor %s is already running", name))
+		return
+	}
+
+	if s.manager.WaitingForTokenAuth(name) {
+		writeAPIError(w, http.StatusConflict, "another client using token auth is still starting, try again later")
+		return
+	}
+
+	// Start blocks until the supervisor is stopped, so it can't run within the request
+	go func() {
+		if err := s.manager.Start(name, false); err != nil {
+			s.logger.Error("Failed to start supervisor from API", slog.String("supervisor", name), slog.Any("error", err))
+		}
+	}()
+
+	writeAPIResponse(w, http.StatusAccepted, apiSupervisor{Name: name, Status: string(bot.Starting)})
+}
+
+func (s *HttpServer) apiStopSupervisor(w http.ResponseWriter, r *http.Request) {
+	name, found := s.apiSupervisorName(w, r)
+	if !found {
+		return
+	}
+
+	if s.manager.GetSupervisorStats(name).SupervisorStatus == "" {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("supervisor %s is not running", name))
+		return
+	}
+
+	s.manager.Stop(name)
+	writeAPIResponse(w, http.StatusOK, s.apiSupervisorSummary(name))
+}
+
+func (s *HttpServer) apiSupervisorStats(w http.ResponseWriter, r *http.Request) {
+	name, found := s.apiSupervisorName(w, r)
+	if !found {
+		return
+	}
+
+	stats := s.manager.GetSupervisorStats(name)
+	runs := make(map[string]*apiRunStats)
+	durations := make(map[string]time.Duration)
+	finished := make(map[string]int)
+	for _, g := range stats.Games {
+		for _, rs := range g.Runs {
+			run, found := runs[rs.Name]
+			if !found {
+				run = &apiRunStats{Name: rs.Name}
+				runs[rs.Name] = run
+			}
+
+			run.Total++
+			switch rs.Reason {
+			case event.FinishedOK:
+				run.Successful++
+			case event.FinishedDied:
+				run.Deaths++
+			case event.FinishedChicken, event.FinishedMercChicken:
+				run.Chickens++
+			case event.FinishedError:
+				run.Errors++
+			}
+			if !rs.FinishedAt.IsZero() {
+				durations[rs.Name] += rs.FinishedAt.Sub(rs.StartedAt)
+				finished[rs.Name]++
+			}
+		}
+	}
+
+	response := apiSupervisorStats{
+		apiSupervisor: s.apiSupervisorSummary(name),
+		Runs:          make([]apiRunStats, 0, len(runs)),
+	}
+	for runName, run := range runs {
+		if finished[runName] > 0 {
+			run.AverageDurationSeconds = durations[runName].Seconds() / float64(finished[runName])
+		}
+		response.Runs = append(response.Runs, *run)
+	}
+	slices.SortFunc(response.Runs, func(a, b apiRunStats) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	writeAPIResponse(w, http.StatusOK, response)
+}
+
+// apiSupervisorName returns the supervisor from the route, writing a 404 if there is no config for it
+func (s *HttpServer) apiSupervisorName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("name")
+	if _, found := config.Characters[name]; !found || name == "template" {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("supervisor %s not found", name))
+		return "", false
+	}
+
+	return name, true
+}
+
+func (s *HttpServer) apiSupervisorSummary(name string) apiSupervisor {
+	stats := s.manager.GetSupervisorStats(name)
+
+	summary := apiSupervisor{
+		Name:     name,
+		Status:   string(stats.SupervisorStatus),
+		Games:    stats.TotalGames(),
+		Drops:    len(stats.Drops),
+		Deaths:   stats.TotalDeaths(),
+		Chickens: stats.TotalChickens(),
+		Errors:   stats.TotalErrors(),
+	}
+	if summary.Status == "" {
+		summary.Status = string(bot.NotStarted)
+	}
+	if !stats.StartedAt.IsZero() {
+		summary.StartedAt = &stats.StartedAt
+	}
+
+	return summary
+}
+
+func writeAPIResponse(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIResponse(w, status, apiError{Error: message})
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/hectorgimenez/koolo/internal/config"
+)
+
+type apiCreateConfigRequest struct {
+	Name string `json:"name"`
+	// CloneFrom is the supervisor to copy the configuration from, the template is used when empty
+	CloneFrom string `json:"cloneFrom"`
+}
+
+type apiConfig struct {
+	Name string `json:"name"`
+}
+
+func (s *HttpServer) apiCreateConfig(w http.ResponseWriter, r *http.Request) {
+	var req apiCreateConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	var err error
+	if req.CloneFrom == "" {
+		err = config.CreateFromTemplate(req.Name)
+	} else {
+		err = config.CloneSupervisorConfig(req.Name, req.CloneFrom)
+	}
+
+	switch {
+	case errors.Is(err, config.ErrInvalidSupervisorName):
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, config.ErrSupervisorExists):
+		writeAPIError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, config.ErrSupervisorNotFound):
+		writeAPIError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		s.logger.Error("Failed to create supervisor config", slog.String("name", req.Name), slog.Any("error", err))
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeAPIResponse(w, http.StatusCreated, apiConfig{Name: req.Name})
+	}
+}
+
+func (s *HttpServer) apiDeleteConfig(w http.ResponseWriter, r *http.Request) {
+	name, found := s.apiSupervisorName(w, r)
+	if !found {
+		return
+	}
+
+	if s.manager.GetSupervisorStats(name).SupervisorStatus != "" {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("supervisor %s is running, stop it before deleting it", name))
+		return
+	}
+
+	if err := config.DeleteSupervisorConfig(name); err != nil {
+		s.logger.Error("Failed to delete supervisor config", slog.String("name", name), slog.Any("error", err))
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
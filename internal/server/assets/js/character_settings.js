@@ -246,4 +246,80 @@ function handleBossStaticThresholdChange() {
         value = 100;
     }
     input.value = value;
-}
\ No newline at end of file
+}
+document.addEventListener('DOMContentLoaded', function () {
+    const form = document.getElementById('character_settings_form');
+    const deleteButton = document.getElementById('delete_supervisor');
+    if (!form) {
+        return;
+    }
+
+    function showError(message) {
+        let error = document.getElementById('config-api-error');
+        if (!error) {
+            error = document.createElement('div');
+            error.id = 'config-api-error';
+            error.className = 'error-message';
+            form.prepend(error);
+        }
+        error.textContent = message;
+        error.scrollIntoView({behavior: 'smooth'});
+    }
+
+    function errorFrom(response) {
+        return response.json()
+            .then(body => body.error || response.statusText)
+            .catch(() => response.statusText);
+    }
+
+    // New supervisors are created through the API first, then the form saves the settings on the created config
+    form.addEventListener('submit', function (e) {
+        if (form.dataset.supervisor !== '') {
+            return;
+        }
+        e.preventDefault();
+
+        const name = form.querySelector('input[name="name"]').value.trim();
+        const cloneFrom = form.querySelector('select[name="cloneFrom"]').value;
+
+        fetch('/api/v1/configs', {
+            method: 'POST',
+            headers: {'Content-Type': 'application/json', 'Authorization': 'Bearer ' + form.dataset.uiToken},
+            body: JSON.stringify({name: name, cloneFrom: cloneFrom})
+        }).then(response => {
+            if (!response.ok) {
+                return errorFrom(response).then(showError);
+            }
+
+            if (cloneFrom !== '') {
+                window.location.href = '/supervisorSettings?supervisor=' + encodeURIComponent(name);
+                return;
+            }
+
+            form.querySelector('input[name="name"]').value = name;
+            form.dataset.supervisor = name;
+            form.submit();
+        }).catch(err => showError(err.message));
+    });
+
+    if (deleteButton) {
+        deleteButton.addEventListener('click', function () {
+            const name = form.dataset.supervisor;
+            if (!confirm(`Delete supervisor ${name}? Its settings will be removed.`)) {
+                return;
+            }
+
+            fetch('/api/v1/configs/' + encodeURIComponent(name), {
+                method: 'DELETE',
+                headers: {'Authorization': 'Bearer ' + form.dataset.uiToken}
+            })
+                .then(response => {
+                    if (!response.ok) {
+                        return errorFrom(response).then(showError);
+                    }
+                    window.location.href = '/';
+                })
+                .catch(err => showError(err.message));
+        });
+    }
+});
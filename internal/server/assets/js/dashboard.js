@@ -63,6 +63,7 @@ let socket;
                 container.appendChild(card);
             }
             updateCharacterCard(card, key, value, data.DropCount[key]);
+            updateCompanionGroup(card, key, data.Companions || []);
         }
 
         // Remove cards for characters that no longer exist
@@ -109,6 +110,7 @@ let socket;
             <div class="character-details">
                 <div class="status-details">
                     <span class="status-badge"></span>
+                    <span class="companion-group"></span>
                 </div>
                 <div class="stats-grid">
                     <div class="stat-item">
@@ -233,6 +235,27 @@ let socket;
         }
     }
 
+    function updateCompanionGroup(card, key, companions) {
+        const companionElement = card.querySelector('.companion-group');
+        if (!companionElement) return;
+
+        const group = companions.find(g => g.Leader === key || (g.Followers || []).includes(key));
+        if (!group) {
+            companionElement.textContent = '';
+            return;
+        }
+
+        let text = group.Leader === key
+            ? `Leading: ${(group.Followers || []).join(', ') || 'no followers'}`
+            : `Following: ${group.Leader}`;
+        if (group.GameName) {
+            text += ` (game: ${group.GameName})`;
+        } else if (!group.LeaderRunning) {
+            text += ' (leader offline)';
+        }
+        companionElement.textContent = text;
+    }
+
     function updateStatusIndicator(statusIndicator, status) {
         statusIndicator.classList.remove('in-game', 'paused', 'stopped');
         if (status === "In game") {
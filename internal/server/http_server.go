@@ -3,7 +3,9 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"embed"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +13,7 @@ import (
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strconv"
@@ -27,7 +30,9 @@ import (
 	"github.com/hectorgimenez/koolo/internal/bot"
 	"github.com/hectorgimenez/koolo/internal/config"
 	ctx "github.com/hectorgimenez/koolo/internal/context"
+	"github.com/hectorgimenez/koolo/internal/event"
 	"github.com/hectorgimenez/koolo/internal/game"
+	"github.com/hectorgimenez/koolo/internal/metrics"
 	"github.com/hectorgimenez/koolo/internal/utils"
 	"github.com/hectorgimenez/koolo/internal/utils/winproc"
 	"github.com/lxn/win"
@@ -38,8 +43,11 @@ type HttpServer struct {
 	logger    *slog.Logger
 	server    *http.Server
 	manager   *bot.SupervisorManager
+	metrics   *metrics.Collector
 	templates *template.Template
 	wsServer  *WebSocketServer
+	// uiToken authorizes the web UI calls to the API, it changes on every start
+	uiToken string
 }
 
 var (
@@ -176,9 +184,18 @@ func (s *HttpServer) BroadcastStatus() {
 	}
 }
 
-func New(logger *slog.Logger, manager *bot.SupervisorManager) (*HttpServer, error) {
+// New creates the local server, metricsCollector can be nil when metrics are disabled
+func New(logger *slog.Logger, manager *bot.SupervisorManager, metricsCollector *metrics.Collector) (*HttpServer, error) {
+	uiToken, err := newUIToken()
+	if err != nil {
+		return nil, err
+	}
+
 	var templates *template.Template
 	helperFuncs := template.FuncMap{
+		"uiToken": func() string {
+			return uiToken
+		},
 		"isInSlice": func(slice []stat.Resist, value string) bool {
 			return slices.Contains(slice, stat.Resist(value))
 		},
@@ -206,7 +223,7 @@ func New(logger *slog.Logger, manager *bot.SupervisorManager) (*HttpServer, erro
 			return result
 		},
 	}
-	templates, err := template.New("").Funcs(helperFuncs).ParseFS(templatesFS, "templates/*.gohtml")
+	templates, err = template.New("").Funcs(helperFuncs).ParseFS(templatesFS, "templates/*.gohtml")
 	if err != nil {
 		return nil, err
 	}
@@ -214,10 +231,21 @@ func New(logger *slog.Logger, manager *bot.SupervisorManager) (*HttpServer, erro
 	return &HttpServer{
 		logger:    logger,
 		manager:   manager,
+		metrics:   metricsCollector,
 		templates: templates,
+		uiToken:   uiToken,
 	}, nil
 }
 
+func newUIToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating the web UI token: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
 func (s *HttpServer) getProcessList(w http.ResponseWriter, r *http.Request) {
 	processes, err := getRunningProcesses()
 	if err != nil {
@@ -395,9 +423,10 @@ func (s *HttpServer) getStatusData() IndexData {
 	}
 
 	return IndexData{
-		Version:   config.Version,
-		Status:    status,
-		DropCount: drops,
+		Version:    config.Version,
+		Status:     status,
+		DropCount:  drops,
+		Companions: s.manager.CompanionGroups(),
 	}
 }
 
@@ -415,11 +444,19 @@ func (s *HttpServer) Listen(port int) error {
 	http.HandleFunc("/debug", s.debugHandler)
 	http.HandleFunc("/debug-data", s.debugData)
 	http.HandleFunc("/drops", s.drops)
+	http.HandleFunc("/screenshots", s.screenshots)
+	http.HandleFunc("/screenshot", s.screenshot)
 	http.HandleFunc("/process-list", s.getProcessList)
 	http.HandleFunc("/attach-process", s.attachProcess)
 	http.HandleFunc("/ws", s.wsServer.HandleWebSocket) // Web socket
 	http.HandleFunc("/initial-data", s.initialData)    // Web socket data
 
+	if s.metrics != nil {
+		http.HandleFunc("/metrics", s.metricsHandler)
+	}
+
+	http.Handle("/api/", s.apiHandler())
+
 	assets, _ := fs.Sub(assetsFS, "assets")
 	http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.FS(assets))))
 
@@ -434,6 +471,25 @@ func (s *HttpServer) Listen(port int) error {
 	return nil
 }
 
+func (s *HttpServer) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	states := make(map[string]string)
+	for _, supervisorName := range s.manager.AvailableSupervisors() {
+		switch s.manager.GetSupervisorStats(supervisorName).SupervisorStatus {
+		case bot.Starting, bot.InGame:
+			states[supervisorName] = metrics.StateRunning
+		case bot.Paused:
+			states[supervisorName] = metrics.StatePaused
+		default:
+			states[supervisorName] = metrics.StateStopped
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := s.metrics.Write(w, states); err != nil {
+		s.logger.Error("Failed to write metrics", slog.Any("error", err))
+	}
+}
+
 func (s *HttpServer) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -488,39 +544,15 @@ func (s *HttpServer) debugHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *HttpServer) startSupervisor(w http.ResponseWriter, r *http.Request) {
-	supervisorList := s.manager.AvailableSupervisors()
 	Supervisor := r.URL.Query().Get("characterName")
 
-	// Get the current auth method for the supervisor we wanna start
-	supCfg, currFound := config.Characters[Supervisor]
-	if !currFound {
-		// There's no config for the current supervisor. THIS SHOULDN'T HAPPEN
+	// There's no config for the current supervisor. THIS SHOULDN'T HAPPEN
+	if _, found := config.Characters[Supervisor]; !found {
 		return
 	}
 
-	// Prevent launching of other clients while there's a client with TokenAuth still starting
-	for _, sup := range supervisorList {
-
-		// If the current don't check against the one we're trying to launch
-		if sup == Supervisor {
-			continue
-		}
-
-		if s.manager.GetSupervisorStats(sup).SupervisorStatus == bot.Starting {
-
-			// Prevent launching if we're using token auth & another client is starting (no matter what auth method)
-			if supCfg.AuthMethod == "TokenAuth" {
-				return
-			}
-
-			// Prevent launching if another client that is using token auth is starting
-			sCfg, found := config.Characters[sup]
-			if found {
-				if sCfg.AuthMethod == "TokenAuth" {
-					return
-				}
-			}
-		}
+	if s.manager.WaitingForTokenAuth(Supervisor) {
+		return
 	}
 
 	s.manager.Start(Supervisor, false)
@@ -557,9 +589,10 @@ func (s *HttpServer) index(w http.ResponseWriter) {
 	}
 
 	s.templates.ExecuteTemplate(w, "index.gohtml", IndexData{
-		Version:   config.Version,
-		Status:    status,
-		DropCount: drops,
+		Version:    config.Version,
+		Status:     status,
+		DropCount:  drops,
+		Companions: s.manager.CompanionGroups(),
 	})
 }
 
@@ -586,6 +619,41 @@ func (s *HttpServer) drops(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *HttpServer) screenshots(w http.ResponseWriter, r *http.Request) {
+	sup := r.URL.Query().Get("supervisor")
+	if _, found := config.Characters[sup]; !found {
+		http.Error(w, "Can't fetch screenshots because the configuration "+sup+" wasn't found", http.StatusNotFound)
+		return
+	}
+
+	files, err := event.ListScreenshots(sup)
+	if err != nil {
+		http.Error(w, "Failed to list screenshots", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(files)
+}
+
+func (s *HttpServer) screenshot(w http.ResponseWriter, r *http.Request) {
+	sup := r.URL.Query().Get("supervisor")
+	if _, found := config.Characters[sup]; !found {
+		http.Error(w, "Can't fetch screenshot because the configuration "+sup+" wasn't found", http.StatusNotFound)
+		return
+	}
+
+	// Only plain file names are allowed, we don't want to serve anything outside the screenshots folder
+	fileName := r.URL.Query().Get("file")
+	if fileName == "" || filepath.Base(fileName) != fileName || filepath.Ext(fileName) != ".jpeg" {
+		http.Error(w, "Invalid screenshot name", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	http.ServeFile(w, r, filepath.Join(event.ScreenshotsDir(sup), fileName))
+}
+
 func validateSchedulerData(cfg *config.CharacterCfg) error {
 	for day := 0; day < 7; day++ {
 
@@ -629,6 +697,7 @@ func (s *HttpServer) config(w http.ResponseWriter, r *http.Request) {
 		newConfig.D2LoDPath = r.Form.Get("d2lodpath")
 		newConfig.UseCustomSettings = r.Form.Get("use_custom_settings") == "true"
 		newConfig.GameWindowArrangement = r.Form.Get("game_window_arrangement") == "true"
+		newConfig.PauseHotkey = strings.TrimSpace(r.Form.Get("pause_hotkey"))
 		// Debug
 		newConfig.Debug.Log = r.Form.Get("debug_log") == "true"
 		newConfig.Debug.Screenshots = r.Form.Get("debug_screenshots") == "true"
@@ -638,6 +707,13 @@ func (s *HttpServer) config(w http.ResponseWriter, r *http.Request) {
 		newConfig.Discord.EnableNewRunMessages = r.Form.Has("enable_new_run_messages")
 		newConfig.Discord.EnableRunFinishMessages = r.Form.Has("enable_run_finish_messages")
 		newConfig.Discord.EnableDiscordChickenMessages = r.Form.Has("enable_discord_chicken_messages")
+		newConfig.Discord.EnableDeathMessages = r.Form.Has("enable_death_messages")
+		newConfig.Discord.EnableErrorMessages = r.Form.Has("enable_error_messages")
+		newConfig.Discord.EnableDropMessages = r.Form.Has("enable_drop_messages")
+		newConfig.Discord.DropQualities = r.Form["discord_drop_qualities[]"]
+		newConfig.Discord.EnableRunSummaryMessages = r.Form.Has("enable_run_summary_messages")
+		newConfig.Discord.RunSummaryGames, _ = strconv.Atoi(r.Form.Get("run_summary_games"))
+		newConfig.Discord.RunSummaryMinutes, _ = strconv.Atoi(r.Form.Get("run_summary_minutes"))
 
 		// Discord admins who can use bot commands
 		discordAdmins := r.Form.Get("discord_admins")
@@ -706,6 +782,7 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		cfg.KillD2OnStop = r.Form.Has("kill_d2_process")
 		cfg.ClassicMode = r.Form.Has("classic_mode")
 		cfg.CloseMiniPanel = r.Form.Has("close_mini_panel")
+		cfg.PauseOnFocus = r.Form.Has("pause_on_focus")
 
 		// Bnet config
 		cfg.Username = r.Form.Get("username")
@@ -934,6 +1011,7 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 		// Companion
 
 		// Companion config
+		cfg.Companion.Enabled = r.Form.Has("companionEnabled")
 		cfg.Companion.Leader = r.Form.Has("companionLeader")
 		cfg.Companion.LeaderName = r.Form.Get("companionLeaderName")
 		cfg.Companion.GameNameTemplate = r.Form.Get("companionGameNameTemplate")
@@ -985,7 +1063,11 @@ func (s *HttpServer) characterSettings(w http.ResponseWriter, r *http.Request) {
 
 	dayNames := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
 
+	supervisors := s.manager.AvailableSupervisors()
+	sort.Strings(supervisors)
+
 	s.templates.ExecuteTemplate(w, "character_settings.gohtml", CharacterSettings{
+		Supervisors:  supervisors,
 		Supervisor:   supervisor,
 		Config:       cfg,
 		DayNames:     dayNames,
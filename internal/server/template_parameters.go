@@ -11,6 +11,7 @@ type IndexData struct {
 	Version      string
 	Status       map[string]bot.Stats
 	DropCount    map[string]int
+	Companions   []bot.CompanionGroupInfo
 }
 
 type DropData struct {
@@ -28,6 +29,7 @@ type CharacterSettings struct {
 	DisabledRuns []string
 	AvailableTZs map[int]string
 	RecipeList   []string
+	Supervisors  []string
 }
 
 type ConfigData struct {
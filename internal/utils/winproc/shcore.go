@@ -0,0 +1,14 @@
+package winproc
+
+import "golang.org/x/sys/windows"
+
+const (
+	PROCESS_PER_MONITOR_DPI_AWARE = 2
+	MDT_EFFECTIVE_DPI             = 0
+)
+
+var (
+	SHCORE                 = windows.NewLazySystemDLL("shcore.dll")
+	GetDpiForMonitor       = SHCORE.NewProc("GetDpiForMonitor")
+	SetProcessDpiAwareness = SHCORE.NewProc("SetProcessDpiAwareness")
+)
@@ -2,6 +2,13 @@ package winproc
 
 import "golang.org/x/sys/windows"
 
+const (
+	MOD_ALT      = 0x0001
+	MOD_CONTROL  = 0x0002
+	MOD_SHIFT    = 0x0004
+	MOD_NOREPEAT = 0x4000
+)
+
 var (
 	USER32             = windows.NewLazySystemDLL("user32.dll")
 	PrintWindow        = USER32.NewProc("PrintWindow")
@@ -11,4 +18,8 @@ var (
 	GetKeyState        = USER32.NewProc("GetKeyState")
 	GetWindowText      = USER32.NewProc("GetWindowTextW")
 	MapVirtualKey      = USER32.NewProc("MapVirtualKeyW")
+	IsWindow           = USER32.NewProc("IsWindow")
+	RegisterHotKey     = USER32.NewProc("RegisterHotKey")
+	UnregisterHotKey   = USER32.NewProc("UnregisterHotKey")
+	PostThreadMessage  = USER32.NewProc("PostThreadMessageW")
 )